@@ -0,0 +1,244 @@
+// Code generated by tools/generateast from ast.spec. DO NOT EDIT.
+
+package ast
+
+import (
+	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/stmt"
+)
+
+// walkChildren walks each of node's children.
+func walkChildren(node Node, fn func(Node) bool) {
+	switch n := node.(type) {
+	case *expr.Binary:
+		Walk(n.Left, fn)
+		Walk(n.Right, fn)
+	case *expr.Logical:
+		Walk(n.Left, fn)
+		Walk(n.Right, fn)
+	case *expr.Conditional:
+		Walk(n.Condition, fn)
+		Walk(n.Then, fn)
+		Walk(n.Else, fn)
+	case *expr.Grouping:
+		Walk(n.Expression, fn)
+	case *expr.Unary:
+		Walk(n.Right, fn)
+	case *expr.Assign:
+		Walk(n.Value, fn)
+	case *expr.CompoundAssign:
+		Walk(n.Target, fn)
+		Walk(n.Value, fn)
+	case *expr.Update:
+		Walk(n.Target, fn)
+	case *expr.Call:
+		Walk(n.Callee, fn)
+		for _, child := range n.Arguments {
+			Walk(child, fn)
+		}
+	case *expr.Get:
+		Walk(n.Object, fn)
+	case *expr.Function:
+		for _, child := range n.Body {
+			Walk(child, fn)
+		}
+	case *expr.List:
+		for _, child := range n.Elements {
+			Walk(child, fn)
+		}
+	case *expr.Map:
+		for _, child := range n.Keys {
+			Walk(child, fn)
+		}
+		for _, child := range n.Values {
+			Walk(child, fn)
+		}
+	case *expr.Index:
+		Walk(n.Object, fn)
+		Walk(n.Index, fn)
+	case *expr.SetIndex:
+		Walk(n.Object, fn)
+		Walk(n.Index, fn)
+		Walk(n.Value, fn)
+	case *stmt.Expression:
+		Walk(n.Expression, fn)
+	case *stmt.Block:
+		for _, child := range n.Statements {
+			Walk(child, fn)
+		}
+	case *stmt.Conditional:
+		Walk(n.Condition, fn)
+		Walk(n.ThenStatement, fn)
+		Walk(n.ElseStatement, fn)
+	case *stmt.Function:
+		for _, child := range n.Body {
+			Walk(child, fn)
+		}
+	case *stmt.Print:
+		Walk(n.Expression, fn)
+	case *stmt.While:
+		Walk(n.Condition, fn)
+		Walk(n.Body, fn)
+		Walk(n.Increment, fn)
+	case *stmt.Var:
+		Walk(n.Initializer, fn)
+	case *stmt.Throw:
+		Walk(n.Value, fn)
+	case *stmt.Try:
+		for _, child := range n.Body {
+			Walk(child, fn)
+		}
+		for _, child := range n.CatchBody {
+			Walk(child, fn)
+		}
+		for _, child := range n.FinallyBody {
+			Walk(child, fn)
+		}
+	case *stmt.Return:
+		Walk(n.Value, fn)
+	}
+}
+
+// rewriteChildren replaces each of node's children with the result of rewriting it.
+func rewriteChildren(node Node, fn func(Node) Node) {
+	switch n := node.(type) {
+	case *expr.Binary:
+		n.Left = rewriteExpr(n.Left, fn)
+		n.Right = rewriteExpr(n.Right, fn)
+	case *expr.Logical:
+		n.Left = rewriteExpr(n.Left, fn)
+		n.Right = rewriteExpr(n.Right, fn)
+	case *expr.Conditional:
+		n.Condition = rewriteExpr(n.Condition, fn)
+		n.Then = rewriteExpr(n.Then, fn)
+		n.Else = rewriteExpr(n.Else, fn)
+	case *expr.Grouping:
+		n.Expression = rewriteExpr(n.Expression, fn)
+	case *expr.Unary:
+		n.Right = rewriteExpr(n.Right, fn)
+	case *expr.Assign:
+		n.Value = rewriteExpr(n.Value, fn)
+	case *expr.CompoundAssign:
+		n.Target = rewriteExpr(n.Target, fn)
+		n.Value = rewriteExpr(n.Value, fn)
+	case *expr.Update:
+		n.Target = rewriteExpr(n.Target, fn)
+	case *expr.Call:
+		n.Callee = rewriteExpr(n.Callee, fn)
+		n.Arguments = rewriteExprs(n.Arguments, fn)
+	case *expr.Get:
+		n.Object = rewriteExpr(n.Object, fn)
+	case *expr.Function:
+		n.Body = rewriteStatements(n.Body, fn)
+	case *expr.List:
+		n.Elements = rewriteExprs(n.Elements, fn)
+	case *expr.Map:
+		n.Keys = rewriteExprs(n.Keys, fn)
+		n.Values = rewriteExprs(n.Values, fn)
+	case *expr.Index:
+		n.Object = rewriteExpr(n.Object, fn)
+		n.Index = rewriteExpr(n.Index, fn)
+	case *expr.SetIndex:
+		n.Object = rewriteExpr(n.Object, fn)
+		n.Index = rewriteExpr(n.Index, fn)
+		n.Value = rewriteExpr(n.Value, fn)
+	case *stmt.Expression:
+		n.Expression = rewriteExpr(n.Expression, fn)
+	case *stmt.Block:
+		n.Statements = rewriteStmts(n.Statements, fn)
+	case *stmt.Conditional:
+		n.Condition = rewriteExpr(n.Condition, fn)
+		n.ThenStatement = rewriteStmt(n.ThenStatement, fn)
+		n.ElseStatement = rewriteStmt(n.ElseStatement, fn)
+	case *stmt.Function:
+		n.Body = rewriteStmts(n.Body, fn)
+	case *stmt.Print:
+		n.Expression = rewriteExpr(n.Expression, fn)
+	case *stmt.While:
+		n.Condition = rewriteExpr(n.Condition, fn)
+		n.Body = rewriteStmt(n.Body, fn)
+		n.Increment = rewriteExpr(n.Increment, fn)
+	case *stmt.Var:
+		n.Initializer = rewriteExpr(n.Initializer, fn)
+	case *stmt.Throw:
+		n.Value = rewriteExpr(n.Value, fn)
+	case *stmt.Try:
+		n.Body = rewriteStmts(n.Body, fn)
+		n.CatchBody = rewriteStmts(n.CatchBody, fn)
+		n.FinallyBody = rewriteStmts(n.FinallyBody, fn)
+	case *stmt.Return:
+		n.Value = rewriteExpr(n.Value, fn)
+	}
+}
+
+func rewriteExpr(node expr.Expr, fn func(Node) Node) expr.Expr {
+	rewritten := Rewrite(node, fn)
+	if rewritten == nil {
+		return nil
+	}
+
+	return rewritten.(expr.Expr)
+}
+
+func rewriteExprs(nodes []expr.Expr, fn func(Node) Node) []expr.Expr {
+	if nodes == nil {
+		return nil
+	}
+
+	rewritten := nodes[:0]
+	for _, node := range nodes {
+		if node := rewriteExpr(node, fn); node != nil {
+			rewritten = append(rewritten, node)
+		}
+	}
+
+	return rewritten
+}
+
+func rewriteStatement(node expr.Statement, fn func(Node) Node) expr.Statement {
+	rewritten := Rewrite(node, fn)
+	if rewritten == nil {
+		return nil
+	}
+
+	return rewritten.(expr.Statement)
+}
+
+func rewriteStatements(nodes []expr.Statement, fn func(Node) Node) []expr.Statement {
+	if nodes == nil {
+		return nil
+	}
+
+	rewritten := nodes[:0]
+	for _, node := range nodes {
+		if node := rewriteStatement(node, fn); node != nil {
+			rewritten = append(rewritten, node)
+		}
+	}
+
+	return rewritten
+}
+
+func rewriteStmt(node stmt.Stmt, fn func(Node) Node) stmt.Stmt {
+	rewritten := Rewrite(node, fn)
+	if rewritten == nil {
+		return nil
+	}
+
+	return rewritten.(stmt.Stmt)
+}
+
+func rewriteStmts(nodes []stmt.Stmt, fn func(Node) Node) []stmt.Stmt {
+	if nodes == nil {
+		return nil
+	}
+
+	rewritten := nodes[:0]
+	for _, node := range nodes {
+		if node := rewriteStmt(node, fn); node != nil {
+			rewritten = append(rewritten, node)
+		}
+	}
+
+	return rewritten
+}
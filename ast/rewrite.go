@@ -0,0 +1,15 @@
+package ast
+
+// Rewrite transforms the tree rooted at node from the bottom up. It rewrites each of node's children, replacing
+// them in place, and then returns fn(node). fn returns the node to use instead, or the node it was given to keep
+// it. An expression must be replaced by an expression and a statement by a statement, except that returning nil
+// removes a node from a list, like the body of a block, and leaves a missing child, like a while loop's Increment,
+// nil. A nil list stays nil, since stmt.Try uses that to mean that a clause is missing.
+func Rewrite(node Node, fn func(Node) Node) Node {
+	if node == nil {
+		return nil
+	}
+
+	rewriteChildren(node, fn)
+	return fn(node)
+}
@@ -0,0 +1,26 @@
+// Package ast traverses syntax trees made of the nodes in the expr and stmt packages, so that code which only
+// cares about a few kinds of node doesn't have to implement every method of expr.Visitor and stmt.Visitor.
+package ast
+
+import "github.com/maleksiuk/golox/toks"
+
+//go:generate go run ../tools/generateast -spec ../tools/generateast/ast.spec -traversal -package ast -out children.go
+
+// Node is an expr.Expr or a stmt.Stmt. Like go/ast.Node, it knows the range of source code that it was parsed
+// from.
+type Node interface {
+	toks.Range
+}
+
+// Walk traverses the tree rooted at node in depth-first order, like go/ast.Inspect. It calls fn(node) and, if fn
+// returns true, walks each of node's children and then calls fn(nil). A node's children are visited in the order
+// of its fields in ast.spec, so all of a map's keys come before its values. Nil children, like a missing else
+// branch, are skipped.
+func Walk(node Node, fn func(Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+
+	walkChildren(node, fn)
+	fn(nil)
+}
@@ -0,0 +1,146 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/maleksiuk/golox/errorreport"
+	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/parser"
+	"github.com/maleksiuk/golox/scanner"
+	"github.com/maleksiuk/golox/stmt"
+	"github.com/maleksiuk/golox/tools"
+)
+
+func scanAndParse(t *testing.T, code string) []stmt.Stmt {
+	errorReport := errorreport.ErrorReport{Printer: errorreport.NewMockPrinter()}
+
+	tokens := scanner.ScanTokens(code, &errorReport)
+	statements := parser.Parse(tokens, &errorReport)
+	if errorReport.HadError {
+		t.Fatalf("Could not parse %q: %q", code, errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+	}
+
+	return statements
+}
+
+// nodeNames walks statement and returns the type of every node it visits, with the children of a node indented
+// under it.
+func nodeNames(statement stmt.Stmt, fn func(Node) bool) string {
+	var names []string
+	depth := 0
+	Walk(statement, func(node Node) bool {
+		if node == nil {
+			depth--
+			return false
+		}
+
+		names = append(names, strings.Repeat("  ", depth)+strings.TrimPrefix(fmt.Sprintf("%T", node), "*"))
+		if !fn(node) {
+			return false
+		}
+
+		depth++
+		return true
+	})
+
+	return strings.Join(names, "\n")
+}
+
+func TestWalk(t *testing.T) {
+	statements := scanAndParse(t, `while (i < 3) { print [i, {"a": f(i)}]; var g = fun () { return; }; }`)
+
+	actual := nodeNames(statements[0], func(Node) bool { return true })
+	expected := strings.Join([]string{
+		"stmt.While",
+		"  expr.Binary",
+		"    expr.Variable",
+		"    expr.Literal",
+		"  stmt.Block",
+		"    stmt.Print",
+		"      expr.List",
+		"        expr.Variable",
+		"        expr.Map",
+		"          expr.Literal",
+		"          expr.Call",
+		"            expr.Variable",
+		"            expr.Variable",
+		"    stmt.Var",
+		"      expr.Function",
+		"        stmt.Return",
+	}, "\n")
+
+	if actual != expected {
+		t.Errorf("Expected the walk to visit\n%v\nbut it visited\n%v", expected, actual)
+	}
+}
+
+func TestWalkSkipsChildren(t *testing.T) {
+	statements := scanAndParse(t, `if (a) print b; else { print c; }`)
+
+	actual := nodeNames(statements[0], func(node Node) bool {
+		_, isBlock := node.(*stmt.Block)
+		return !isBlock
+	})
+	expected := strings.Join([]string{
+		"stmt.Conditional",
+		"  expr.Variable",
+		"  stmt.Print",
+		"    expr.Variable",
+		"  stmt.Block",
+	}, "\n")
+
+	if actual != expected {
+		t.Errorf("Expected the walk to visit\n%v\nbut it visited\n%v", expected, actual)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	statements := scanAndParse(t, `{ print x + 1; throw x; print -x; }`)
+
+	block := Rewrite(statements[0], func(node Node) Node {
+		switch n := node.(type) {
+		case *expr.Variable:
+			if n.Name.Lexeme == "x" {
+				return &expr.Literal{Value: lox.Int(2)}
+			}
+		case *expr.Unary:
+			return n.Right
+		case *stmt.Throw:
+			return nil
+		}
+		return node
+	}).(*stmt.Block)
+
+	if len(block.Statements) != 2 {
+		t.Fatalf("Expected the throw statement to be removed, but there are %d statements", len(block.Statements))
+	}
+
+	printed := []string{
+		tools.PrintAst(block.Statements[0].(*stmt.Print).Expression),
+		tools.PrintAst(block.Statements[1].(*stmt.Print).Expression),
+	}
+	if printed[0] != "(+ 2 1)" || printed[1] != "2" {
+		t.Errorf("Expected the rewritten expressions to be (+ 2 1) and 2, but they were %v", printed)
+	}
+}
+
+func TestRewriteKeepsMissingChildren(t *testing.T) {
+	statements := scanAndParse(t, `if (a) print a; try { print a; } finally { print a; }`)
+
+	keep := func(node Node) Node { return node }
+	conditional := Rewrite(statements[0], keep).(*stmt.Conditional)
+	try := Rewrite(statements[1], keep).(*stmt.Try)
+
+	if conditional.ElseStatement != nil {
+		t.Errorf("Expected the missing else branch to stay nil, but it was %v", conditional.ElseStatement)
+	}
+	if try.CatchBody != nil {
+		t.Errorf("Expected the missing catch clause to stay nil, but it was %v", try.CatchBody)
+	}
+	if len(try.FinallyBody) != 1 {
+		t.Errorf("Expected the finally clause to keep its statement, but it has %d", len(try.FinallyBody))
+	}
+}
@@ -1,9 +1,15 @@
 package errorreport
 
+import "fmt"
+
 type ErrorReport struct {
 	HadError        bool
 	HadRuntimeError bool
 	Printer         Printer
+
+	// File names the script that errors are reported for. It is empty for the main script, whose errors only give
+	// the line.
+	File string
 }
 
 func NewErrorReport() ErrorReport {
@@ -12,10 +18,27 @@ func NewErrorReport() ErrorReport {
 
 func (report *ErrorReport) Report(line int, where string, message string) {
 	report.HadError = true
-	report.Printer.Printf("[line %d] Error %v: %v\n", line, where, message)
+	if where == "" {
+		report.Printer.Printf("%v Error: %v\n", location(report.File, line), message)
+		return
+	}
+	report.Printer.Printf("%v Error %v: %v\n", location(report.File, line), where, message)
 }
 
 func (report *ErrorReport) ReportRuntimeError(line int, message string) {
+	report.ReportRuntimeErrorIn(report.File, line, message)
+}
+
+// ReportRuntimeErrorIn reports a runtime error raised by the code in file, which is empty for the main script.
+func (report *ErrorReport) ReportRuntimeErrorIn(file string, line int, message string) {
 	report.HadRuntimeError = true
-	report.Printer.Printf("[line %d] Runtime error: %v\n", line, message)
+	report.Printer.Printf("%v Runtime error: %v\n", location(file, line), message)
+}
+
+func location(file string, line int) string {
+	if file == "" {
+		return fmt.Sprintf("[line %d]", line)
+	}
+
+	return fmt.Sprintf("[%v, line %d]", file, line)
 }
@@ -0,0 +1,5 @@
+// Package expr defines the expression nodes of the syntax tree. The nodes are generated from
+// tools/generateast/ast.spec, so add or change them there and run go generate.
+package expr
+
+//go:generate go run ../tools/generateast -spec ../tools/generateast/ast.spec -package expr
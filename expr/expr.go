@@ -1,11 +1,15 @@
+// Code generated by tools/generateast from ast.spec. DO NOT EDIT.
+
 package expr
 
 import (
+	"github.com/maleksiuk/golox/lox"
 	"github.com/maleksiuk/golox/toks"
 )
 
 type Expr interface {
-	Accept(visitor Visitor) interface{}
+	toks.Range
+	Accept(visitor Visitor) lox.Value
 }
 
 type Binary struct {
@@ -14,79 +18,365 @@ type Binary struct {
 	Right    Expr
 }
 
-func (binary *Binary) Accept(visitor Visitor) interface{} {
+func (binary *Binary) Accept(visitor Visitor) lox.Value {
 	return visitor.VisitBinary(binary)
 }
 
+func (binary *Binary) Pos() toks.Position { return binary.Left.Pos() }
+
+func (binary *Binary) End() toks.Position { return binary.Right.End() }
+
 type Logical struct {
 	Left     Expr
 	Operator toks.Token
 	Right    Expr
 }
 
-func (logical *Logical) Accept(visitor Visitor) interface{} {
+func (logical *Logical) Accept(visitor Visitor) lox.Value {
 	return visitor.VisitLogical(logical)
 }
 
+func (logical *Logical) Pos() toks.Position { return logical.Left.Pos() }
+
+func (logical *Logical) End() toks.Position { return logical.Right.End() }
+
+type Conditional struct {
+	Condition Expr
+	Then      Expr
+	Else      Expr
+}
+
+func (conditional *Conditional) Accept(visitor Visitor) lox.Value {
+	return visitor.VisitConditional(conditional)
+}
+
+func (conditional *Conditional) Pos() toks.Position { return conditional.Condition.Pos() }
+
+func (conditional *Conditional) End() toks.Position { return conditional.Else.End() }
+
 type Grouping struct {
+	LeftParen  toks.Token
 	Expression Expr
+	RightParen toks.Token
 }
 
-func (grouping *Grouping) Accept(visitor Visitor) interface{} {
+func (grouping *Grouping) Accept(visitor Visitor) lox.Value {
 	return visitor.VisitGrouping(grouping)
 }
 
+func (grouping *Grouping) Pos() toks.Position { return grouping.LeftParen.Pos }
+
+func (grouping *Grouping) End() toks.Position { return grouping.RightParen.End() }
+
+// Literal covers the source code from ValuePos to ValueEnd. A literal that the optimizer folded covers the
+// expression that it replaced.
 type Literal struct {
-	Value interface{}
+	Value    lox.Value
+	ValuePos toks.Position
+	ValueEnd toks.Position
 }
 
-func (literal *Literal) Accept(visitor Visitor) interface{} {
+func (literal *Literal) Accept(visitor Visitor) lox.Value {
 	return visitor.VisitLiteral(literal)
 }
 
+func (literal *Literal) Pos() toks.Position { return literal.ValuePos }
+
+func (literal *Literal) End() toks.Position { return literal.ValueEnd }
+
 type Unary struct {
 	Operator toks.Token
 	Right    Expr
 }
 
-func (unary *Unary) Accept(visitor Visitor) interface{} {
+func (unary *Unary) Accept(visitor Visitor) lox.Value {
 	return visitor.VisitUnary(unary)
 }
 
+func (unary *Unary) Pos() toks.Position { return unary.Operator.Pos }
+
+func (unary *Unary) End() toks.Position { return unary.Right.End() }
+
+// Variable and Assign have a nil Local until the resolver finds that they refer to a local variable. Globals keep
+// a nil Local and are looked up by name.
 type Variable struct {
-	Name toks.Token
+	Name  toks.Token
+	Local *Local
 }
 
-func (variable *Variable) Accept(visitor Visitor) interface{} {
+func (variable *Variable) Accept(visitor Visitor) lox.Value {
 	return visitor.VisitVariable(variable)
 }
 
+func (variable *Variable) Pos() toks.Position { return variable.Name.Pos }
+
+func (variable *Variable) End() toks.Position { return variable.Name.End() }
+
 type Assign struct {
 	Name  toks.Token
 	Value Expr
+	Local *Local
 }
 
-func (assign *Assign) Accept(visitor Visitor) interface{} {
+func (assign *Assign) Accept(visitor Visitor) lox.Value {
 	return visitor.VisitAssign(assign)
 }
 
+func (assign *Assign) Pos() toks.Position { return assign.Name.Pos }
+
+func (assign *Assign) End() toks.Position { return assign.Value.End() }
+
+// CompoundAssign is an assignment such as 'a += 1'. Operator is the compound assignment token (e.g., '+=').
+type CompoundAssign struct {
+	Target   Expr
+	Operator toks.Token
+	Value    Expr
+}
+
+func (compoundAssign *CompoundAssign) Accept(visitor Visitor) lox.Value {
+	return visitor.VisitCompoundAssign(compoundAssign)
+}
+
+func (compoundAssign *CompoundAssign) Pos() toks.Position { return compoundAssign.Target.Pos() }
+
+func (compoundAssign *CompoundAssign) End() toks.Position { return compoundAssign.Value.End() }
+
+// Update is an increment or decrement ('++' or '--'), either before (Prefix) or after its target.
+type Update struct {
+	Target   Expr
+	Operator toks.Token
+	Prefix   bool
+}
+
+func (update *Update) Accept(visitor Visitor) lox.Value {
+	return visitor.VisitUpdate(update)
+}
+
+func (update *Update) Pos() toks.Position {
+	if update.Prefix {
+		return update.Operator.Pos
+	}
+	return update.Target.Pos()
+}
+
+func (update *Update) End() toks.Position {
+	if update.Prefix {
+		return update.Target.End()
+	}
+	return update.Operator.End()
+}
+
+// A call ends with its closing parenthesis, which is Paren.
 type Call struct {
 	Callee    Expr
 	Paren     toks.Token
 	Arguments []Expr
 }
 
-func (call *Call) Accept(visitor Visitor) interface{} {
+func (call *Call) Accept(visitor Visitor) lox.Value {
 	return visitor.VisitCall(call)
 }
 
+func (call *Call) Pos() toks.Position { return call.Callee.Pos() }
+
+func (call *Call) End() toks.Position { return call.Paren.End() }
+
+type Get struct {
+	Object Expr
+	Name   toks.Token
+}
+
+func (get *Get) Accept(visitor Visitor) lox.Value {
+	return visitor.VisitGet(get)
+}
+
+func (get *Get) Pos() toks.Position { return get.Object.Pos() }
+
+func (get *Get) End() toks.Position { return get.Name.End() }
+
+// Function is an anonymous function, either 'fun (params) { body }' or '(params) => expression'. Keyword is
+// 'fun' or the '(' that starts an arrow function. The statements in Body are stmt.Stmt nodes, which are converted
+// with stmt.FunctionBody and stmt.NewFunctionBody. BodyEnd is the position just after the closing brace, or after
+// the expression of an arrow function.
+type Function struct {
+	Keyword toks.Token
+	Params  []toks.Token
+	Body    []Statement
+	BodyEnd toks.Position
+}
+
+func (function *Function) Accept(visitor Visitor) lox.Value {
+	return visitor.VisitFunction(function)
+}
+
+func (function *Function) Pos() toks.Position { return function.Keyword.Pos }
+
+func (function *Function) End() toks.Position { return function.BodyEnd }
+
+type List struct {
+	Bracket      toks.Token
+	Elements     []Expr
+	RightBracket toks.Token
+}
+
+func (list *List) Accept(visitor Visitor) lox.Value {
+	return visitor.VisitList(list)
+}
+
+func (list *List) Pos() toks.Position { return list.Bracket.Pos }
+
+func (list *List) End() toks.Position { return list.RightBracket.End() }
+
+type Map struct {
+	Brace      toks.Token
+	Keys       []Expr
+	Values     []Expr
+	RightBrace toks.Token
+}
+
+func (m *Map) Accept(visitor Visitor) lox.Value {
+	return visitor.VisitMap(m)
+}
+
+func (m *Map) Pos() toks.Position { return m.Brace.Pos }
+
+func (m *Map) End() toks.Position { return m.RightBrace.End() }
+
+type Index struct {
+	Object       Expr
+	Bracket      toks.Token
+	Index        Expr
+	RightBracket toks.Token
+}
+
+func (index *Index) Accept(visitor Visitor) lox.Value {
+	return visitor.VisitIndex(index)
+}
+
+func (index *Index) Pos() toks.Position { return index.Object.Pos() }
+
+func (index *Index) End() toks.Position { return index.RightBracket.End() }
+
+type SetIndex struct {
+	Object  Expr
+	Bracket toks.Token
+	Index   Expr
+	Value   Expr
+}
+
+func (setIndex *SetIndex) Accept(visitor Visitor) lox.Value {
+	return visitor.VisitSetIndex(setIndex)
+}
+
+func (setIndex *SetIndex) Pos() toks.Position { return setIndex.Object.Pos() }
+
+func (setIndex *SetIndex) End() toks.Position { return setIndex.Value.End() }
+
 type Visitor interface {
-	VisitBinary(binary *Binary) interface{}
-	VisitGrouping(grouping *Grouping) interface{}
-	VisitLiteral(literal *Literal) interface{}
-	VisitUnary(unary *Unary) interface{}
-	VisitVariable(variable *Variable) interface{}
-	VisitAssign(assign *Assign) interface{}
-	VisitLogical(logical *Logical) interface{}
-	VisitCall(call *Call) interface{}
+	VisitBinary(binary *Binary) lox.Value
+	VisitLogical(logical *Logical) lox.Value
+	VisitConditional(conditional *Conditional) lox.Value
+	VisitGrouping(grouping *Grouping) lox.Value
+	VisitLiteral(literal *Literal) lox.Value
+	VisitUnary(unary *Unary) lox.Value
+	VisitVariable(variable *Variable) lox.Value
+	VisitAssign(assign *Assign) lox.Value
+	VisitCompoundAssign(compoundAssign *CompoundAssign) lox.Value
+	VisitUpdate(update *Update) lox.Value
+	VisitCall(call *Call) lox.Value
+	VisitGet(get *Get) lox.Value
+	VisitFunction(function *Function) lox.Value
+	VisitList(list *List) lox.Value
+	VisitMap(m *Map) lox.Value
+	VisitIndex(index *Index) lox.Value
+	VisitSetIndex(setIndex *SetIndex) lox.Value
+}
+
+// BaseVisitor is a Visitor whose methods do nothing. Embed it in a visitor that only needs to handle some of
+// the nodes.
+type BaseVisitor struct{}
+
+func (BaseVisitor) VisitBinary(binary *Binary) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitLogical(logical *Logical) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitConditional(conditional *Conditional) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitGrouping(grouping *Grouping) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitLiteral(literal *Literal) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitUnary(unary *Unary) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitVariable(variable *Variable) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitAssign(assign *Assign) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitCompoundAssign(compoundAssign *CompoundAssign) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitUpdate(update *Update) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitCall(call *Call) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitGet(get *Get) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitFunction(function *Function) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitList(list *List) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitMap(m *Map) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitIndex(index *Index) lox.Value {
+	var zero lox.Value
+	return zero
+}
+
+func (BaseVisitor) VisitSetIndex(setIndex *SetIndex) lox.Value {
+	var zero lox.Value
+	return zero
 }
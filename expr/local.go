@@ -0,0 +1,8 @@
+package expr
+
+// Local says where a local variable lives: in the environment Depth scopes out from the current one, at index
+// Slot.
+type Local struct {
+	Depth int
+	Slot  int
+}
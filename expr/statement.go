@@ -0,0 +1,11 @@
+package expr
+
+import "github.com/maleksiuk/golox/toks"
+
+// Statement is a statement node in the body of a Function. Only the nodes in the stmt package have a StatementNode
+// method, so every Statement is a stmt.Stmt. Function can't hold a []stmt.Stmt because the stmt package imports
+// this one.
+type Statement interface {
+	toks.Range
+	StatementNode()
+}
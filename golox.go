@@ -2,77 +2,159 @@ package main
 
 import (
 	"bufio"
-	"errors"
+	"flag"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/maleksiuk/golox/errorreport"
 	"github.com/maleksiuk/golox/interpreter"
+	"github.com/maleksiuk/golox/loxtest"
 	"github.com/maleksiuk/golox/parser"
 	"github.com/maleksiuk/golox/scanner"
 )
 
+// Exit statuses, following the BSD sysexits.h conventions like jlox does.
+const (
+	exitUsage    = 64 // the command line was wrong
+	exitDataErr  = 65 // the script had a scanner or parser error
+	exitNoInput  = 66 // the script couldn't be read
+	exitSoftware = 70 // the script had a runtime error
+)
+
+// exitTestFailure is the exit status of "golox -test" when a script doesn't meet its expectations.
+const exitTestFailure = 1
+
 func main() {
-	args := os.Args[1:]
-	argCount := len(args)
+	testDir := flag.String("test", "", "run the scripts in `dir` against their expectation comments")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  golox                      start the REPL\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  golox script [args ...]    run script, passing it args\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  golox -test dir            run the tests in dir\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
 
-	i := interpreter.NewInterpreter()
+	args := flag.Args()
+	argCount := len(args)
 
-	switch {
-	case argCount > 1:
-		fmt.Println("Usage: golox [script]")
-	case argCount == 1:
-		err := runFile(i, args[0])
-		if err != nil {
-			os.Exit(1)
+	if *testDir != "" {
+		if argCount > 0 {
+			flag.Usage()
+			os.Exit(exitUsage)
 		}
-	default:
-		runPrompt(i)
+		os.Exit(runTests(*testDir))
 	}
+
+	// The REPL and the script's readLine calls share one buffered reader so that neither reads ahead of the other.
+	stdin := bufio.NewReader(os.Stdin)
+
+	if argCount == 0 {
+		i := interpreter.NewInterpreter(interpreter.WithStdin(stdin))
+		runPrompt(i, stdin)
+		return
+	}
+
+	// Everything after the script path is passed to the script.
+	i := interpreter.NewInterpreter(interpreter.WithStdin(stdin), interpreter.WithArgs(args[1:]))
+	os.Exit(runFile(i, args[0]))
 }
 
-func runFile(i interpreter.Interpreter, path string) error {
+// runFile runs the script at path and returns the process exit status.
+func runFile(i interpreter.Interpreter, path string) int {
 	errorReport := errorreport.NewErrorReport()
 
-	buf, err := ioutil.ReadFile(path)
+	file, err := os.Open(path)
 	if err != nil {
 		log.Print(err)
-		return err
+		return exitNoInput
+	}
+	defer file.Close()
+
+	if err := i.SetScriptPath(path); err != nil {
+		log.Print(err)
+		return exitNoInput
 	}
 
-	run(i, string(buf), &errorReport)
+	run(i, file, &errorReport)
 
+	if code, exited := i.ExitCode(); exited {
+		return code
+	}
 	if errorReport.HadError {
-		return errors.New("Scanner error")
+		return exitDataErr
 	}
 	if errorReport.HadRuntimeError {
-		return errors.New("Runtime error")
+		return exitSoftware
 	}
 
-	return nil
+	return 0
 }
 
-func runPrompt(i interpreter.Interpreter) {
+// runTests runs every script in dir against its expectation comments and reports which ones failed.
+func runTests(dir string) int {
+	results, err := loxtest.RunDir(dir)
+	if err != nil {
+		log.Print(err)
+		return exitNoInput
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Passed() {
+			fmt.Printf("PASS %v\n", result.Path)
+			continue
+		}
+
+		failed++
+		fmt.Printf("FAIL %v\n", result.Path)
+		for _, failure := range result.Failures {
+			fmt.Printf("    %v\n", failure)
+		}
+	}
+
+	fmt.Printf("%d passed, %d failed.\n", len(results)-failed, failed)
+	if failed > 0 {
+		return exitTestFailure
+	}
+
+	return 0
+}
+
+func runPrompt(i interpreter.Interpreter, stdin *bufio.Reader) {
 	errorReport := errorreport.NewErrorReport()
 
-	scanner := bufio.NewScanner(os.Stdin)
 	fmt.Print("> ")
-	for scanner.Scan() {
-		run(i, scanner.Text(), &errorReport)
-		errorReport.HadError = false
-		fmt.Print("> ")
-	}
+	for {
+		line, err := stdin.ReadString('\n')
+		if line != "" {
+			run(i, strings.NewReader(strings.TrimSuffix(line, "\n")), &errorReport)
+			errorReport.HadError = false
+
+			if code, exited := i.ExitCode(); exited {
+				os.Exit(code)
+			}
+		}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Print("> ")
 	}
 }
 
-func run(i interpreter.Interpreter, line string, errorReport *errorreport.ErrorReport) {
-	tokens := scanner.ScanTokens(line, errorReport)
-	statements := parser.Parse(tokens, errorReport)
+// run scans, parses and interprets the Lox code read from source. The code is scanned as the parser needs it, so a
+// large script is never held in memory all at once.
+func run(i interpreter.Interpreter, source io.Reader, errorReport *errorreport.ErrorReport) {
+	statements := parser.ParseStream(scanner.New(source, errorReport), errorReport)
 
 	// Stop if there was a syntax error.
 	if errorReport.HadError {
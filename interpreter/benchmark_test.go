@@ -0,0 +1,113 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/maleksiuk/golox/errorreport"
+	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/stmt"
+	"github.com/maleksiuk/golox/toks"
+)
+
+func benchmarkScript(b *testing.B, code string) {
+	statements := scanAndParse(code)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter()
+		interpreter.Interpret(statements, &errorReport)
+
+		if errorReport.HadError || errorReport.HadRuntimeError {
+			b.Fatal(errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+		}
+	}
+}
+
+func BenchmarkFib(b *testing.B) {
+	benchmarkScript(b, `
+	  fun fib(n) {
+	    if (n < 2) return n;
+	    return fib(n - 1) + fib(n - 2);
+	  }
+	  var result = fib(20);
+	`)
+}
+
+func BenchmarkLoop(b *testing.B) {
+	benchmarkScript(b, `
+	  fun sum(n) {
+	    var total = 0;
+	    for (var i = 0; i < n; i = i + 1) {
+	      var square = i * i;
+	      total = total + square;
+	    }
+	    return total;
+	  }
+	  var result = sum(100000);
+	`)
+}
+
+// BenchmarkBinaryOp evaluates a single arithmetic expression over and over, so its allocations per op show the
+// cost of producing intermediate values.
+func BenchmarkBinaryOp(b *testing.B) {
+	statements := scanAndParse("var a = 3; var b = 4; var c = (a + b) * (a - b) / 2;")
+	binary := statements[2].(*stmt.Var).Initializer
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		interpreter.evaluate(binary)
+	}
+}
+
+// closureDepth is how many scopes out from the reading code BenchmarkSlotLookup and BenchmarkNameLookup declare
+// their variable, like in a closure nested a few functions and blocks deep.
+const closureDepth = 8
+
+// variableSink keeps the compiler from optimizing away the variable reads being measured.
+var variableSink lox.Value
+
+// BenchmarkSlotLookup reads a variable through the slot that the resolver gives it, which is how locals are found.
+// Compare it with BenchmarkNameLookup.
+func BenchmarkSlotLookup(b *testing.B) {
+	env := &environment{}
+	env.define("x", lox.Int(1))
+	for d := 0; d < closureDepth; d++ {
+		scope := newEnvironment(env)
+		env = &scope
+	}
+
+	name := toks.Token{TokenType: toks.Identifier, Lexeme: "x"}
+	local := &expr.Local{Depth: closureDepth, Slot: 0}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		variableSink = env.get(name, local)
+	}
+}
+
+// BenchmarkNameLookup reads the same variable by looking its name up in the map of every scope on the way out,
+// which is how globals are found and how every variable was found before the resolver assigned slots.
+func BenchmarkNameLookup(b *testing.B) {
+	env := &environment{variables: map[string]lox.Value{"x": lox.Int(1)}}
+	for d := 0; d < closureDepth; d++ {
+		env = &environment{parent: env, variables: make(map[string]lox.Value)}
+	}
+	env.globals = env
+
+	name := toks.Token{TokenType: toks.Identifier, Lexeme: "x"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		variableSink = env.get(name, nil)
+	}
+}
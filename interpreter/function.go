@@ -0,0 +1,73 @@
+package interpreter
+
+import (
+	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/stmt"
+	"github.com/maleksiuk/golox/toks"
+)
+
+// loxFunction is a function declared in Lox code, either by a 'fun' declaration or an anonymous function
+// expression. It closes over the environment that was active when it was declared.
+type loxFunction struct {
+	name    string // empty for anonymous functions
+	params  []toks.Token
+	body    []stmt.Stmt
+	closure *environment
+}
+
+// returnSignal is panicked by a 'return' statement and recovered by the function call it returns from.
+type returnSignal struct {
+	value lox.Value
+}
+
+func (function *loxFunction) Call(i Interpreter, args []lox.Value) (result lox.Value) {
+	env := newEnvironment(function.closure)
+	for idx, param := range function.params {
+		env.define(param.Lexeme, args[idx])
+	}
+
+	defer locateRuntimeError(function.closure.globals.module)
+	defer func() {
+		if e := recover(); e != nil {
+			signal, ok := e.(returnSignal)
+			if !ok {
+				panic(e)
+			}
+
+			result = signal.value
+		}
+	}()
+
+	i.executeBlock(function.body, env)
+	return lox.Nil
+}
+
+func (function *loxFunction) Arity() int {
+	return len(function.params)
+}
+
+func (function *loxFunction) String() string {
+	if function.name == "" {
+		return "<fn>"
+	}
+
+	return "<fn " + function.name + ">"
+}
+
+func (i Interpreter) VisitFunction(function *expr.Function) lox.Value {
+	return lox.Object(&loxFunction{params: function.Params, body: stmt.FunctionBody(function), closure: i.env})
+}
+
+func (i Interpreter) VisitStatementFunction(function *stmt.Function) {
+	i.env.define(function.Name.Lexeme, lox.Object(&loxFunction{name: function.Name.Lexeme, params: function.Params, body: function.Body, closure: i.env}))
+}
+
+func (i Interpreter) VisitStatementReturn(r *stmt.Return) {
+	value := lox.Nil
+	if r.Value != nil {
+		value = i.evaluate(r.Value)
+	}
+
+	panic(returnSignal{value: value})
+}
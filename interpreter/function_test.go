@@ -0,0 +1,97 @@
+package interpreter
+
+import (
+	"testing"
+)
+
+func TestFunctionDeclarationAndReturn(t *testing.T) {
+	code := `
+	  fun fib(n) {
+		  if (n < 2) return n;
+		  return fib(n - 1) + fib(n - 2);
+	  }
+	  var result = fib(10);
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+	result := interpreter.GetVariableValue("result").(int64)
+
+	var expected int64 = 55
+	if result != expected {
+		t.Errorf("Expected result to be %v, but it was %v.", expected, result)
+	}
+}
+
+func TestClosures(t *testing.T) {
+	code := `
+	  fun makeCounter() {
+		  var count = 0;
+		  fun increment() {
+			  count = count + 1;
+			  return count;
+		  }
+		  return increment;
+	  }
+	  var counter = makeCounter();
+	  counter();
+	  var result = counter();
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+	result := interpreter.GetVariableValue("result").(int64)
+
+	var expected int64 = 2
+	if result != expected {
+		t.Errorf("Expected result to be %v, but it was %v.", expected, result)
+	}
+}
+
+func TestAnonymousFunctions(t *testing.T) {
+	code := `
+	  fun apply(f, a, b) {
+		  return f(a, b);
+	  }
+	  var offset = 100;
+	  var sum = apply(fun (a, b) { return a + b; }, 1, 2);
+	  var product = apply((a, b) => a * b + offset, 3, 4);
+	  var constant = (() => "hi")();
+	  fun (unused) {};
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	if sum := interpreter.GetVariableValue("sum").(int64); sum != 3 {
+		t.Errorf("Expected sum to be 3, but it was %v.", sum)
+	}
+
+	if product := interpreter.GetVariableValue("product").(int64); product != 112 {
+		t.Errorf("Expected product to be 112, but it was %v.", product)
+	}
+
+	if constant := interpreter.GetVariableValue("constant").(string); constant != "hi" {
+		t.Errorf("Expected constant to be %q, but it was %q.", "hi", constant)
+	}
+}
+
+func TestArityError(t *testing.T) {
+	code := `
+	  var add = (a, b) => a + b;
+	  add(1);
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	assertRuntimeError(t, errorReport, "[line 3] Runtime error: Expected 2 arguments but got 1.\n")
+}
@@ -1,121 +1,292 @@
 package interpreter
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"math/rand"
+	"os"
 	"time"
 
 	"github.com/maleksiuk/golox/errorreport"
 	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/optimizer"
 	"github.com/maleksiuk/golox/stmt"
 	"github.com/maleksiuk/golox/toks"
 )
 
+// environment holds the variables of one scope. Globals are kept by name in the global environment. Local
+// scopes keep their variables in declaration order, which is the order the resolver gives them slots in, so
+// they can be found by index.
 type environment struct {
 	parent    *environment
-	variables map[string]interface{}
+	globals   *environment
+	variables map[string]lox.Value
+	values    []lox.Value
+
+	// module is the name of the imported module that a global environment belongs to. It is empty for the main
+	// script.
+	module string
 }
 
-func (e *environment) define(name string, val interface{}) {
-	e.variables[name] = val
+func (e *environment) define(name string, val lox.Value) {
+	if e.variables != nil {
+		e.variables[name] = val
+		return
+	}
+
+	e.values = append(e.values, val)
 }
 
-func (e *environment) assign(name toks.Token, val interface{}) {
-	nameValue := name.Lexeme
-	_, ok := e.variables[nameValue]
-	if ok {
-		e.variables[nameValue] = val
-	} else {
-		if e.parent != nil {
-			e.parent.assign(name, val)
-		} else {
-			message := fmt.Sprintf("Undefined variable '%v'.", nameValue)
-			panic(runtimeError{token: name, message: message})
-		}
+// ancestor returns the environment depth scopes out from this one.
+func (e *environment) ancestor(depth int) *environment {
+	env := e
+	for d := 0; d < depth; d++ {
+		env = env.parent
 	}
+
+	return env
 }
 
-func (e *environment) get(name toks.Token) interface{} {
-	val, ok := e.variables[name.Lexeme]
+// get returns the value of a variable. local is nil for a global.
+func (e *environment) get(name toks.Token, local *expr.Local) lox.Value {
+	if local != nil {
+		return e.ancestor(local.Depth).values[local.Slot]
+	}
 
-	if !ok {
-		if e.parent != nil {
-			return e.parent.get(name)
-		}
+	scope := e.globalScope(name.Lexeme)
+	if scope == nil {
+		message := fmt.Sprintf("Undefined variable '%v'.", name.Lexeme)
+		panic(runtimeError{token: name, message: message})
+	}
+
+	return scope.variables[name.Lexeme]
+}
+
+func (e *environment) assign(name toks.Token, local *expr.Local, val lox.Value) {
+	if local != nil {
+		e.ancestor(local.Depth).values[local.Slot] = val
+		return
+	}
 
+	scope := e.globalScope(name.Lexeme)
+	if scope == nil {
 		message := fmt.Sprintf("Undefined variable '%v'.", name.Lexeme)
 		panic(runtimeError{token: name, message: message})
 	}
 
-	return val
+	scope.variables[name.Lexeme] = val
+}
+
+// globalScope returns the global environment that defines name, or nil if none does. A module's globals are a
+// child of the natives, which are used when the module doesn't define the name itself.
+func (e *environment) globalScope(name string) *environment {
+	for env := e.globals; env != nil; env = env.parent {
+		if _, ok := env.variables[name]; ok {
+			return env
+		}
+	}
+
+	return nil
 }
 
 type Callable interface {
-	Call(i Interpreter, args []interface{}) interface{}
+	Call(i Interpreter, args []lox.Value) lox.Value
 	Arity() int
 }
 
-// Interpreter implements execution of Lox statements.
+// Interpreter implements execution of Lox statements. It only holds a pointer to its state, so it can be passed
+// around by value and used as an expr.Visitor without allocating.
 type Interpreter struct {
-	env *environment
+	*state
+}
+
+// state is what an Interpreter works with. env is the environment of the code being executed, which blocks and
+// function calls replace and restore when they finish.
+type state struct {
+	env             *environment
+	modules         *moduleLoader
+	errorReport     *errorreport.ErrorReport
+	random          *rand.Rand
+	stdin           *bufio.Reader
+	stdout          io.Writer
+	allowFilesystem bool
+	args            []string
+	exit            *exitStatus
 }
 
 type runtimeError struct {
 	token   toks.Token
 	message string
+
+	// thrown is true when the error was raised by a 'throw' statement, in which case value holds the
+	// thrown Lox value.
+	thrown bool
+	value  lox.Value
+
+	// located is true once module names the imported module whose code raised the error (empty for the main
+	// script), so that the error is reported with the right file.
+	located bool
+	module  string
+}
+
+// locateRuntimeError is deferred around the execution of code from module, which is empty for the main script. It
+// records the module in a runtime error raised by the code, unless code that it called has already been recorded.
+func locateRuntimeError(module string) {
+	if e := recover(); e != nil {
+		if err, ok := e.(runtimeError); ok && !err.located {
+			err.located = true
+			err.module = module
+			panic(err)
+		}
+
+		panic(e)
+	}
 }
 
-// TODO: Should I move this somewhere else?
-type clockFunction struct {
+// caughtValue returns the value that a catch clause binds for this error. Errors raised by the interpreter
+// itself are turned into loxError values so that scripts can inspect them.
+func (err runtimeError) caughtValue() lox.Value {
+	if err.thrown {
+		return err.value
+	}
+
+	return lox.Object(&loxError{message: err.message, line: err.token.Line})
 }
 
-func (function clockFunction) Call(i Interpreter, args []interface{}) interface{} {
-	return float64(time.Now().UnixNano()) / 1e+9
+// propertyHolder is implemented by values that support property access with the '.' operator.
+type propertyHolder interface {
+	get(name toks.Token) lox.Value
 }
 
-func (function clockFunction) Arity() int {
-	return 0
+// loxError is the value a catch clause receives for a runtime error raised by the interpreter.
+type loxError struct {
+	message string
+	line    int
+}
+
+func (err *loxError) get(name toks.Token) lox.Value {
+	switch name.Lexeme {
+	case "message":
+		return lox.String(err.message)
+	case "line":
+		return lox.Int(int64(err.line))
+	}
+
+	panic(runtimeError{token: name, message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)})
 }
 
+// breakSignal and continueSignal are panicked by 'break' and 'continue' statements and recovered by the
+// enclosing loop. The parser guarantees that there is always an enclosing loop.
+type breakSignal struct{}
+
+type continueSignal struct{}
+
+// newEnvironment returns a new local scope inside parent.
 func newEnvironment(parent *environment) environment {
-	return environment{variables: make(map[string]interface{}), parent: parent}
+	return environment{parent: parent, globals: parent.globals}
+}
+
+// newGlobals returns a new global environment containing the native functions and constants.
+func newGlobals() *environment {
+	env := &environment{variables: make(map[string]lox.Value)}
+	env.globals = env
+
+	defineNatives(env, coreNatives)
+	defineNatives(env, mathNatives)
+	defineNatives(env, stringNatives)
+	defineNatives(env, mapNatives)
+	defineNatives(env, jsonNatives)
+	defineNatives(env, ioNatives)
+	defineNatives(env, systemNatives)
+	for name, value := range mathConstants {
+		env.define(name, value)
+	}
+	return env
 }
 
-// NewInterpreter returns a new Interpreter with an empty environment.
-func NewInterpreter() Interpreter {
-	env := newEnvironment(nil)
-	env.define("clock", clockFunction{})
-	return Interpreter{env: &env}
+// newModuleGlobals returns the global environment of the named module. It is a child of a new environment holding
+// the natives, so that the module can use them without them becoming members of the module.
+func newModuleGlobals(module string) *environment {
+	env := &environment{parent: newGlobals(), variables: make(map[string]lox.Value), module: module}
+	env.globals = env
+	return env
 }
 
-// Interpret executes a program (list of statements).
+// Option configures an Interpreter created by NewInterpreter.
+type Option func(i *Interpreter)
+
+// WithRandomSeed seeds the generator used by the random and randomInt functions so that scripts using them
+// behave the same way on every run.
+func WithRandomSeed(seed int64) Option {
+	return func(i *Interpreter) {
+		i.random = rand.New(rand.NewSource(seed))
+	}
+}
+
+// NewInterpreter returns a new Interpreter with an environment containing only the native functions.
+func NewInterpreter(options ...Option) Interpreter {
+	i := Interpreter{&state{
+		env:             newGlobals(),
+		modules:         newModuleLoader(),
+		random:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		stdin:           bufio.NewReader(os.Stdin),
+		stdout:          os.Stdout,
+		allowFilesystem: true,
+		exit:            &exitStatus{},
+	}}
+
+	for _, option := range options {
+		option(&i)
+	}
+
+	return i
+}
+
+// Interpret executes a program (list of statements). The program is resolved first, and isn't run if that
+// reports an error. It is optimized after that, so that errors in code the optimizer removes are still reported.
 func (i Interpreter) Interpret(statements []stmt.Stmt, errorReport *errorreport.ErrorReport) {
 	defer func() {
 		if e := recover(); e != nil {
+			if signal, ok := e.(exitSignal); ok {
+				i.exit.exited = true
+				i.exit.code = signal.code
+				return
+			}
+
 			// This will intentionally re-panic if it's not a runtime error.
 			runtimeError := e.(runtimeError)
-			errorReport.ReportRuntimeError(runtimeError.token.Line, runtimeError.message)
+			errorReport.ReportRuntimeErrorIn(runtimeError.module, runtimeError.token.Line, runtimeError.message)
 		}
 	}()
 
+	if !resolve(statements, errorReport) {
+		return
+	}
+	statements = optimizer.Optimize(statements)
+
+	i.errorReport = errorReport
 	for _, statement := range statements {
 		i.execute(statement)
 	}
 }
 
-// GetVariableValue gets the value for the variable with name 'name'. Used for testing only.
+// GetVariableValue gets the value for the variable with name 'name' as a plain Go value (see lox.Value's
+// Interface method). Used for testing only.
 func (i Interpreter) GetVariableValue(name string) interface{} {
 	token := toks.Token{TokenType: toks.Identifier, Literal: nil, Lexeme: name, Line: 0}
-	return i.env.get(token)
+	return i.env.get(token, nil).Interface()
 }
 
-func (i Interpreter) VisitLogical(logical *expr.Logical) interface{} {
+func (i Interpreter) VisitLogical(logical *expr.Logical) lox.Value {
 	left := i.evaluate(logical.Left)
 	if logical.Operator.TokenType == toks.Or {
-		if isTruthy(left) {
+		if left.Truthy() {
 			return left
 		}
 	} else {
-		if !isTruthy(left) {
+		if !left.Truthy() {
 			return left
 		}
 	}
@@ -124,117 +295,284 @@ func (i Interpreter) VisitLogical(logical *expr.Logical) interface{} {
 	return right
 }
 
-func (i Interpreter) VisitBinary(binary *expr.Binary) interface{} {
+func (i Interpreter) VisitBinary(binary *expr.Binary) lox.Value {
 	left := i.evaluate(binary.Left)
 	right := i.evaluate(binary.Right)
 
-	switch binary.Operator.TokenType {
+	return binaryOperation(binary.Operator, left, right)
+}
+
+// binaryOperation applies the operator to the already-evaluated operands.
+func binaryOperation(operator toks.Token, left lox.Value, right lox.Value) lox.Value {
+	switch operator.TokenType {
 	case toks.Star:
-		checkNumberOperands(binary.Operator, left, right)
-		return left.(float64) * right.(float64)
+		return arithmetic(operator, lox.Multiply, left, right)
 	case toks.Slash:
-		checkNumberOperands(binary.Operator, left, right)
-		return left.(float64) / right.(float64)
+		checkNumberOperands(operator, left, right)
+		return lox.Divide(left, right)
+	case toks.TildeSlash:
+		return arithmetic(operator, lox.FloorDivide, left, right)
+	case toks.Percent:
+		return arithmetic(operator, lox.Modulo, left, right)
+	case toks.StarStar:
+		return arithmetic(operator, lox.Power, left, right)
 	case toks.Minus:
-		checkNumberOperands(binary.Operator, left, right)
-		return left.(float64) - right.(float64)
+		return arithmetic(operator, lox.Subtract, left, right)
 	case toks.Plus:
-		{
-			l, leftOk := left.(float64)
-			r, rightOk := right.(float64)
-
-			if leftOk && rightOk {
-				return l + r
-			}
+		if left.IsNumber() && right.IsNumber() {
+			return arithmetic(operator, lox.Add, left, right)
 		}
 
-		{
-			l, leftOk := left.(string)
-			r, rightOk := right.(string)
-
-			if leftOk && rightOk {
-				return l + r
-			}
+		if left.IsString() && right.IsString() {
+			return lox.String(left.AsString() + right.AsString())
 		}
 
-		panic(runtimeError{token: binary.Operator, message: "Operands must be two numbers or two strings."})
+		panic(runtimeError{token: operator, message: "Operands must be two numbers or two strings."})
 	case toks.Greater:
-		checkNumberOperands(binary.Operator, left, right)
-		return left.(float64) > right.(float64)
+		checkNumberOperands(operator, left, right)
+		return lox.Bool(lox.Less(right, left))
 	case toks.GreaterEqual:
-		checkNumberOperands(binary.Operator, left, right)
-		return left.(float64) >= right.(float64)
+		checkNumberOperands(operator, left, right)
+		return lox.Bool(lox.LessEqual(right, left))
 	case toks.Less:
-		checkNumberOperands(binary.Operator, left, right)
-		return left.(float64) < right.(float64)
+		checkNumberOperands(operator, left, right)
+		return lox.Bool(lox.Less(left, right))
 	case toks.LessEqual:
-		checkNumberOperands(binary.Operator, left, right)
-		return left.(float64) <= right.(float64)
+		checkNumberOperands(operator, left, right)
+		return lox.Bool(lox.LessEqual(left, right))
 	case toks.EqualEqual:
-		return isEqual(left, right)
+		return lox.Bool(lox.Equal(left, right))
 	case toks.BangEqual:
-		return !isEqual(left, right)
+		return lox.Bool(!lox.Equal(left, right))
+	case toks.Ampersand:
+		checkIntegerOperands(operator, left, right)
+		return lox.BitwiseAnd(left, right)
+	case toks.Pipe:
+		checkIntegerOperands(operator, left, right)
+		return lox.BitwiseOr(left, right)
+	case toks.Caret:
+		checkIntegerOperands(operator, left, right)
+		return lox.BitwiseXor(left, right)
+	case toks.LessLess:
+		return shift(operator, lox.ShiftLeft, left, right)
+	case toks.GreaterGreater:
+		return shift(operator, lox.ShiftRight, left, right)
 	}
 
 	// Unreachable.
-	return nil
+	return lox.Nil
 }
 
-func (i Interpreter) VisitGrouping(grouping *expr.Grouping) interface{} {
+// arithmetic applies fn to two numbers. Integer overflow and division by zero are reported at the operator.
+func arithmetic(operator toks.Token, fn func(lox.Value, lox.Value) (lox.Value, error), left lox.Value, right lox.Value) lox.Value {
+	checkNumberOperands(operator, left, right)
+
+	result, err := fn(left, right)
+	if err != nil {
+		panic(runtimeError{token: operator, message: err.Error()})
+	}
+
+	return result
+}
+
+// shift applies a shift function to two integers. A negative shift count is reported at the operator.
+func shift(operator toks.Token, fn func(lox.Value, lox.Value) (lox.Value, error), left lox.Value, right lox.Value) lox.Value {
+	checkIntegerOperands(operator, left, right)
+
+	result, err := fn(left, right)
+	if err != nil {
+		panic(runtimeError{token: operator, message: err.Error()})
+	}
+
+	return result
+}
+
+func (i Interpreter) VisitConditional(conditional *expr.Conditional) lox.Value {
+	if i.evaluate(conditional.Condition).Truthy() {
+		return i.evaluate(conditional.Then)
+	}
+
+	return i.evaluate(conditional.Else)
+}
+
+func (i Interpreter) VisitGrouping(grouping *expr.Grouping) lox.Value {
 	return i.evaluate(grouping.Expression)
 }
 
-func (i Interpreter) VisitLiteral(literal *expr.Literal) interface{} {
+func (i Interpreter) VisitLiteral(literal *expr.Literal) lox.Value {
 	return literal.Value
 }
 
-func (i Interpreter) VisitUnary(unary *expr.Unary) interface{} {
+func (i Interpreter) VisitUnary(unary *expr.Unary) lox.Value {
 	right := i.evaluate(unary.Right)
 
 	if unary.Operator.TokenType == toks.Bang {
-		return !isTruthy(right)
+		return lox.Bool(!right.Truthy())
+	} else if unary.Operator.TokenType == toks.Tilde {
+		checkIntegerOperand(unary.Operator, right)
+		return lox.BitwiseNot(right)
 	} else if unary.Operator.TokenType == toks.Minus {
 		checkNumberOperand(unary.Operator, right)
-		return -right.(float64)
+
+		value, err := lox.Negate(right)
+		if err != nil {
+			panic(runtimeError{token: unary.Operator, message: err.Error()})
+		}
+		return value
 	}
 
 	// Unreachable.
-	return nil
+	return lox.Nil
 }
 
-func (i Interpreter) VisitVariable(v *expr.Variable) interface{} {
-	return i.env.get(v.Name)
+func (i Interpreter) VisitVariable(v *expr.Variable) lox.Value {
+	return i.env.get(v.Name, v.Local)
 }
 
-func (i Interpreter) VisitAssign(assign *expr.Assign) interface{} {
+func (i Interpreter) VisitAssign(assign *expr.Assign) lox.Value {
 	value := i.evaluate(assign.Value)
-	i.env.assign(assign.Name, value)
+	i.env.assign(assign.Name, assign.Local, value)
 
 	return value
 }
 
-func (i Interpreter) VisitCall(call *expr.Call) interface{} {
+// compoundOperators maps each compound assignment operator to the binary operator it applies.
+var compoundOperators = map[toks.TokenType]toks.TokenType{
+	toks.PlusEqual:    toks.Plus,
+	toks.MinusEqual:   toks.Minus,
+	toks.StarEqual:    toks.Star,
+	toks.SlashEqual:   toks.Slash,
+	toks.PercentEqual: toks.Percent,
+}
+
+// reference is an assignable location, used by operators that both read and write their target.
+type reference struct {
+	get func() lox.Value
+	set func(value lox.Value)
+}
+
+// evaluateReference evaluates the parts of an assignment target (e.g., the list and index of 'a[i]') exactly
+// once and returns a reference to the location they identify.
+func (i Interpreter) evaluateReference(target expr.Expr) reference {
+	switch t := target.(type) {
+	case *expr.Variable:
+		return reference{
+			get: func() lox.Value { return i.env.get(t.Name, t.Local) },
+			set: func(value lox.Value) { i.env.assign(t.Name, t.Local, value) },
+		}
+	case *expr.Index:
+		object := i.evaluate(t.Object)
+		key := i.evaluate(t.Index)
+		return reference{
+			get: func() lox.Value { return getIndex(t.Bracket, object, key) },
+			set: func(value lox.Value) { setIndexValue(t.Bracket, object, key, value) },
+		}
+	}
+
+	// Unreachable: the parser only allows assignable targets.
+	panic(fmt.Sprintf("unexpected assignment target %T", target))
+}
+
+func (i Interpreter) VisitCompoundAssign(assign *expr.CompoundAssign) lox.Value {
+	operator := assign.Operator
+	operator.TokenType = compoundOperators[assign.Operator.TokenType]
+
+	ref := i.evaluateReference(assign.Target)
+	current := ref.get()
+	value := binaryOperation(operator, current, i.evaluate(assign.Value))
+	ref.set(value)
+
+	return value
+}
+
+func (i Interpreter) VisitUpdate(update *expr.Update) lox.Value {
+	ref := i.evaluateReference(update.Target)
+	current := ref.get()
+	checkNumberOperand(update.Operator, current)
+
+	step := lox.Add
+	if update.Operator.TokenType == toks.MinusMinus {
+		step = lox.Subtract
+	}
+	value, err := step(current, lox.Int(1))
+	if err != nil {
+		panic(runtimeError{token: update.Operator, message: err.Error()})
+	}
+	ref.set(value)
+
+	if update.Prefix {
+		return value
+	}
+	return current
+}
+
+func (i Interpreter) VisitCall(call *expr.Call) lox.Value {
 	callee := i.evaluate(call.Callee)
 
-	args := make([]interface{}, len(call.Arguments))
+	args := make([]lox.Value, len(call.Arguments))
 	for idx, arg := range call.Arguments {
 		args[idx] = i.evaluate(arg)
 	}
 
-	callable, ok := callee.(Callable)
+	callable, ok := callee.AsObject().(Callable)
 	if ok {
-		if len(args) != callable.Arity() {
-			panic(runtimeError{token: call.Paren, message: fmt.Sprintf("Expected %v arguments but got %v.", len(args), callable.Arity())})
-		}
-		return callable.Call(i, args)
+		checkArity(callable, call.Paren, len(args))
+		return i.call(callable, call.Paren, args)
 	} else {
 		panic(runtimeError{token: call.Paren, message: "Can only call functions and classes."})
 	}
 }
 
+// optionalArgs is implemented by callables whose trailing parameters may be left out.
+type optionalArgs interface {
+	MinArity() int
+}
+
+func checkArity(callable Callable, paren toks.Token, argCount int) {
+	arity := callable.Arity()
+	minArity := arity
+	if optional, ok := callable.(optionalArgs); ok {
+		minArity = optional.MinArity()
+	}
+
+	if argCount >= minArity && argCount <= arity {
+		return
+	}
+
+	if minArity == arity {
+		panic(runtimeError{token: paren, message: fmt.Sprintf("Expected %v arguments but got %v.", arity, argCount)})
+	}
+	panic(runtimeError{token: paren, message: fmt.Sprintf("Expected %v to %v arguments but got %v.", minArity, arity, argCount)})
+}
+
+func (i Interpreter) call(callable Callable, paren toks.Token, args []lox.Value) lox.Value {
+	defer func() {
+		if e := recover(); e != nil {
+			err, ok := e.(nativeError)
+			if !ok {
+				panic(e)
+			}
+
+			panic(runtimeError{token: paren, message: err.message})
+		}
+	}()
+
+	return callable.Call(i, args)
+}
+
+func (i Interpreter) VisitGet(get *expr.Get) lox.Value {
+	object := i.evaluate(get.Object)
+
+	if holder, ok := object.AsObject().(propertyHolder); ok {
+		return holder.get(get.Name)
+	}
+
+	panic(runtimeError{token: get.Name, message: "Only instances have properties."})
+}
+
 func (i Interpreter) VisitStatementPrint(p *stmt.Print) {
 	val := i.evaluate(p.Expression)
-	fmt.Println(stringify(val))
+	fmt.Fprintln(i.stdout, stringify(val))
 }
 
 func (i Interpreter) VisitStatementExpression(e *stmt.Expression) {
@@ -243,14 +581,14 @@ func (i Interpreter) VisitStatementExpression(e *stmt.Expression) {
 
 func (i Interpreter) VisitStatementConditional(conditional *stmt.Conditional) {
 	result := i.evaluate(conditional.Condition)
-	if isTruthy(result) {
+	if result.Truthy() {
 		i.execute(conditional.ThenStatement)
 	} else if conditional.ElseStatement != nil {
 		i.execute(conditional.ElseStatement)
 	}
 }
 
-func (i Interpreter) VisitBlock(block *stmt.Block) {
+func (i Interpreter) VisitStatementBlock(block *stmt.Block) {
 	i.executeBlock(block.Statements, newEnvironment(i.env))
 }
 
@@ -267,7 +605,7 @@ func (i Interpreter) executeBlock(statements []stmt.Stmt, env environment) {
 }
 
 func (i Interpreter) VisitStatementVar(v *stmt.Var) {
-	var val interface{} = nil
+	val := lox.Nil
 	if v.Initializer != nil {
 		val = i.evaluate(v.Initializer)
 	}
@@ -275,42 +613,87 @@ func (i Interpreter) VisitStatementVar(v *stmt.Var) {
 	i.env.define(v.Name.Lexeme, val)
 }
 
-func (i Interpreter) VisitStatementFunction(function *stmt.Function) {
-	// TODO
-}
-
 func (i Interpreter) VisitStatementWhile(while *stmt.While) {
-	for isTruthy(i.evaluate(while.Condition)) {
-		i.execute(while.Body)
+	for i.evaluate(while.Condition).Truthy() {
+		if broke := i.executeLoopBody(while.Body); broke {
+			return
+		}
+
+		if while.Increment != nil {
+			i.evaluate(while.Increment)
+		}
 	}
 }
 
-func isTruthy(val interface{}) bool {
-	if val == nil {
-		return false
-	}
-	if b, ok := val.(bool); ok {
-		return b
-	}
+// executeLoopBody executes one iteration of a loop body and returns true if it ended with a 'break'.
+func (i Interpreter) executeLoopBody(body stmt.Stmt) (broke bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			switch e.(type) {
+			case breakSignal:
+				broke = true
+			case continueSignal:
+				broke = false
+			default:
+				panic(e)
+			}
+		}
+	}()
+
+	i.execute(body)
+	return false
+}
+
+func (i Interpreter) VisitStatementBreak(b *stmt.Break) {
+	panic(breakSignal{})
+}
 
-	return true
+func (i Interpreter) VisitStatementContinue(c *stmt.Continue) {
+	panic(continueSignal{})
 }
 
-func isEqual(left interface{}, right interface{}) bool {
-	if left == nil && right == nil {
-		return true
+func (i Interpreter) VisitStatementThrow(throw *stmt.Throw) {
+	value := i.evaluate(throw.Value)
+	panic(runtimeError{token: throw.Keyword, message: stringify(value), thrown: true, value: value})
+}
+
+func (i Interpreter) VisitStatementTry(try *stmt.Try) {
+	if try.FinallyBody != nil {
+		// The finally block runs no matter how we leave the try statement, including 'break', 'continue'
+		// and uncaught errors.
+		defer i.executeBlock(try.FinallyBody, newEnvironment(i.env))
 	}
-	if left == nil || right == nil {
-		return false
+
+	if try.CatchBody == nil {
+		i.executeBlock(try.Body, newEnvironment(i.env))
+		return
 	}
 
-	return left == right
+	if caught := i.executeTryBody(try.Body); caught != nil {
+		env := newEnvironment(i.env)
+		env.define(try.CatchParam.Lexeme, caught.caughtValue())
+		i.executeBlock(try.CatchBody, env)
+	}
 }
 
-func (i Interpreter) evaluate(expression expr.Expr) interface{} {
-	// str := tools.PrintAst(expression)
-	// fmt.Println(str)
+// executeTryBody executes the body of a try statement and returns the runtime error that escaped it, if any.
+func (i Interpreter) executeTryBody(statements []stmt.Stmt) (caught *runtimeError) {
+	defer func() {
+		if e := recover(); e != nil {
+			err, ok := e.(runtimeError)
+			if !ok {
+				panic(e)
+			}
+
+			caught = &err
+		}
+	}()
 
+	i.executeBlock(statements, newEnvironment(i.env))
+	return nil
+}
+
+func (i Interpreter) evaluate(expression expr.Expr) lox.Value {
 	return expression.Accept(i)
 }
 
@@ -318,29 +701,48 @@ func (i Interpreter) execute(statement stmt.Stmt) {
 	statement.Accept(i)
 }
 
-func checkNumberOperand(operator toks.Token, operand interface{}) {
-	if _, ok := operand.(float64); ok {
+func checkNumberOperand(operator toks.Token, operand lox.Value) {
+	if operand.IsNumber() {
 		return
 	}
 
 	panic(runtimeError{token: operator, message: "Operand must be a number."})
 }
 
-func checkNumberOperands(operator toks.Token, operand1 interface{}, operand2 interface{}) {
-	_, ok1 := operand1.(float64)
-	_, ok2 := operand1.(float64)
+func checkNumberOperands(operator toks.Token, operand1 lox.Value, operand2 lox.Value) {
+	if operand1.IsNumber() && operand2.IsNumber() {
+		return
+	}
+
+	panic(runtimeError{token: operator, message: "Operands must be numbers."})
+}
+
+// checkIntegerOperand raises an error unless the operand is an integer or a float with no fractional part.
+func checkIntegerOperand(operator toks.Token, operand lox.Value) {
+	if _, ok := operand.ToInt(); ok {
+		return
+	}
 
+	panic(runtimeError{token: operator, message: "Operand must be an integer."})
+}
+
+func checkIntegerOperands(operator toks.Token, operand1 lox.Value, operand2 lox.Value) {
+	_, ok1 := operand1.ToInt()
+	_, ok2 := operand2.ToInt()
 	if ok1 && ok2 {
 		return
 	}
 
-	panic(runtimeError{token: operator, message: "Operands must be numbers."})
+	panic(runtimeError{token: operator, message: "Operands must be integers."})
 }
 
-func stringify(val interface{}) string {
-	if val == nil {
-		return "nil"
+func stringify(val lox.Value) string {
+	switch v := val.AsObject().(type) {
+	case *loxError:
+		return v.message
+	case *module:
+		return fmt.Sprintf("<module %v>", v.name)
 	}
 
-	return fmt.Sprintf("%v", val)
+	return val.String()
 }
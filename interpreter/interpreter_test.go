@@ -21,6 +21,13 @@ func scanAndParse(code string) []stmt.Stmt {
 	return parser.Parse(tokens, &errorReport)
 }
 
+func assertRuntimeError(t *testing.T, errorReport errorreport.ErrorReport, expected string) {
+	errorMessages := errorReport.Printer.(*errorreport.MockPrinter).GetStrings()
+	if !errorReport.HadRuntimeError || len(errorMessages) != 1 || errorMessages[0] != expected {
+		t.Errorf("Expected a single runtime error %q, but got %q.", expected, errorMessages)
+	}
+}
+
 func TestInterpretOrStatement(t *testing.T) {
 	code := `
 	  var a = 5;
@@ -86,6 +93,243 @@ func TestInterpretArithmetic(t *testing.T) {
 	}
 }
 
+func TestInterpretUnicodeIdentifiers(t *testing.T) {
+	code := `
+	  var π = 3.5;
+	  var größe = π * 2;
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	if größe := interpreter.GetVariableValue("größe"); größe != 7.0 {
+		t.Errorf("Expected größe to be 7, but it was %v.", größe)
+	}
+}
+
+func TestInterpretConditional(t *testing.T) {
+	code := `
+	  var a = 5;
+	  var result = a > 10 ? "big" : a > 3 ? "medium" : "small";
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+	result := interpreter.GetVariableValue("result").(string)
+
+	var expected = "medium"
+	if result != expected {
+		t.Errorf("Expected result to be %v, but it was %v.", expected, result)
+	}
+}
+
+func TestInterpretModuloAndExponent(t *testing.T) {
+	code := `
+	  var modulo = -7 % 3;
+	  var power = 2 ** 3 ** 2;
+	  var negated = -2 ** 2;
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	if modulo := interpreter.GetVariableValue("modulo").(int64); modulo != -1 {
+		t.Errorf("Expected modulo to be -1, but it was %v.", modulo)
+	}
+
+	if power := interpreter.GetVariableValue("power").(int64); power != 512 {
+		t.Errorf("Expected power to be 512, but it was %v.", power)
+	}
+
+	if negated := interpreter.GetVariableValue("negated").(int64); negated != -4 {
+		t.Errorf("Expected negated to be -4, but it was %v.", negated)
+	}
+}
+
+func TestInterpretIntegers(t *testing.T) {
+	code := `
+	  var big = 9007199254740993 + 1;
+	  var sum = 1 + 2;
+	  var mixed = 1 + 0.5;
+	  var quotient = 7 / 2;
+	  var floored = -7 ~/ 2;
+	  var flooredFloat = 7.5 ~/ 2;
+	  var inverse = 2 ** -1;
+	  var same = 1 == 1.0;
+	  var text = str(3.0) + " " + str(0.5) + " " + str(10000000.0) + " " + str(0.0001);
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{
+		"big":          int64(9007199254740994),
+		"sum":          int64(3),
+		"mixed":        1.5,
+		"quotient":     3.5,
+		"floored":      int64(-4),
+		"flooredFloat": 3.0,
+		"inverse":      0.5,
+		"same":         true,
+		"text":         "3 0.5 1.0E7 1.0E-4",
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v (%T), but it was %v (%T).", name, value, value, actual, actual)
+		}
+	}
+}
+
+func TestInterpretBitwiseOperators(t *testing.T) {
+	code := `
+	  var bitAnd = 0b1100 & 0b1010;
+	  var bitOr = 0b1100 | 0b1010;
+	  var bitXor = 0b1100 ^ 0b1010;
+	  var bitNot = ~0;
+	  var left = 1 << 4;
+	  var right = -16 >> 2;
+	  var masked = 6 & 2 == 2;
+	  var whole = 4.0 | 1;
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{
+		"bitAnd": int64(8),
+		"bitOr":  int64(14),
+		"bitXor": int64(6),
+		"bitNot": int64(-1),
+		"left":   int64(16),
+		"right":  int64(-4),
+		"masked": true,
+		"whole":  int64(5),
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestIntegerErrors(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{"9223372036854775807 + 1;", "[line 1] Runtime error: Integer overflow.\n"},
+		{"-9223372036854775807 - 2;", "[line 1] Runtime error: Integer overflow.\n"},
+		{"4294967296 * 4294967296;", "[line 1] Runtime error: Integer overflow.\n"},
+		{"2 ** 63;", "[line 1] Runtime error: Integer overflow.\n"},
+		{"var i = 9223372036854775807; i++;", "[line 1] Runtime error: Integer overflow.\n"},
+		{"var i = -9223372036854775807 - 1; -i;", "[line 1] Runtime error: Integer overflow.\n"},
+		{"1 ~/ 0;", "[line 1] Runtime error: Integer division by zero.\n"},
+		{"1 % 0;", "[line 1] Runtime error: Integer division by zero.\n"},
+		{"1 ~/ \"a\";", "[line 1] Runtime error: Operands must be numbers.\n"},
+		{"1.5 & 1;", "[line 1] Runtime error: Operands must be integers.\n"},
+		{"1 | \"a\";", "[line 1] Runtime error: Operands must be integers.\n"},
+		{"~0.5;", "[line 1] Runtime error: Operand must be an integer.\n"},
+		{"1 << -1;", "[line 1] Runtime error: Shift count must not be negative.\n"},
+	}
+
+	for _, test := range tests {
+		statements := scanAndParse(test.code)
+
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter()
+		interpreter.Interpret(statements, &errorReport)
+
+		assertRuntimeError(t, errorReport, test.expected)
+	}
+}
+
+func TestExponentRequiresNumbers(t *testing.T) {
+	code := `
+	  var power = 2 ** "3";
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	assertRuntimeError(t, errorReport, "[line 2] Runtime error: Operands must be numbers.\n")
+}
+
+func TestInterpretCompoundAssignment(t *testing.T) {
+	code := `
+	  var a = 10;
+	  a += 5;
+	  a -= 3;
+	  a *= 2;
+	  a /= 4;
+	  a %= 4;
+	  var s = "abc";
+	  s += "def";
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	if a := interpreter.GetVariableValue("a").(float64); a != 2 {
+		t.Errorf("Expected a to be 2, but it was %v.", a)
+	}
+
+	if s := interpreter.GetVariableValue("s").(string); s != "abcdef" {
+		t.Errorf("Expected s to be %q, but it was %q.", "abcdef", s)
+	}
+}
+
+func TestInterpretIncrementAndDecrement(t *testing.T) {
+	code := `
+	  var i = 0;
+	  var postfix = i++;
+	  var prefix = ++i;
+	  var down = i--;
+	  --i;
+	  var total = 0;
+	  for (var j = 0; j < 4; j++) total += j;
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]int64{"postfix": 0, "prefix": 2, "down": 2, "i": 0, "total": 6}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name).(int64); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestIncrementRequiresNumber(t *testing.T) {
+	code := `
+	  var s = "abc";
+	  s++;
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	assertRuntimeError(t, errorReport, "[line 3] Runtime error: Operand must be a number.\n")
+}
+
 func TestInterpretWhile(t *testing.T) {
 	code := `
 	  var result = 0;
@@ -98,9 +342,9 @@ func TestInterpretWhile(t *testing.T) {
 	errorReport := newMockErrorReport()
 	interpreter := NewInterpreter()
 	interpreter.Interpret(statements, &errorReport)
-	result := interpreter.GetVariableValue("result").(float64)
+	result := interpreter.GetVariableValue("result").(int64)
 
-	var expected = 5.0
+	var expected int64 = 5
 	if result != expected {
 		t.Errorf("Expected result to be %v, but it was %v.", expected, result)
 	}
@@ -118,14 +362,189 @@ func TestInterpretFor(t *testing.T) {
 	errorReport := newMockErrorReport()
 	interpreter := NewInterpreter()
 	interpreter.Interpret(statements, &errorReport)
-	result := interpreter.GetVariableValue("result").(float64)
+	result := interpreter.GetVariableValue("result").(int64)
+
+	var expected int64 = 4
+	if result != expected {
+		t.Errorf("Expected result to be %v, but it was %v.", expected, result)
+	}
+}
+
+func TestInterpretBreak(t *testing.T) {
+	code := `
+	  var result = 0;
+	  while (true) {
+		  result = result + 1;
+		  if (result == 3) break;
+	  }
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+	result := interpreter.GetVariableValue("result").(int64)
+
+	var expected int64 = 3
+	if result != expected {
+		t.Errorf("Expected result to be %v, but it was %v.", expected, result)
+	}
+}
+
+func TestInterpretContinueRunsForIncrement(t *testing.T) {
+	code := `
+	  var result = 0;
+	  for (var i = 0; i < 6; i = i + 1) {
+		  if (i == 2 or i == 4) continue;
+		  result = result + i;
+	  }
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+	result := interpreter.GetVariableValue("result").(int64)
+
+	var expected int64 = 9
+	if result != expected {
+		t.Errorf("Expected result to be %v, but it was %v.", expected, result)
+	}
+}
+
+func TestInterpretBreakOnlyExitsInnermostLoop(t *testing.T) {
+	code := `
+	  var result = 0;
+	  for (var i = 0; i < 3; i = i + 1) {
+		  while (true) {
+			  break;
+		  }
+		  result = result + 1;
+	  }
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+	result := interpreter.GetVariableValue("result").(int64)
 
-	var expected = 4.0
+	var expected int64 = 3
 	if result != expected {
 		t.Errorf("Expected result to be %v, but it was %v.", expected, result)
 	}
 }
 
+func TestCatchRuntimeError(t *testing.T) {
+	code := `
+	  var message;
+	  var line;
+	  try {
+		  var a = 1 +
+		    "one";
+	  } catch (e) {
+		  message = e.message;
+		  line = e.line;
+	  }
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	if errorReport.HadRuntimeError {
+		t.Error("Expected the runtime error to be caught.")
+	}
+
+	message := interpreter.GetVariableValue("message").(string)
+	if message != "Operands must be two numbers or two strings." {
+		t.Errorf("Expected message to be the runtime error message, but it was %q.", message)
+	}
+
+	line := interpreter.GetVariableValue("line").(int64)
+	if line != 5 {
+		t.Errorf("Expected line to be 5, but it was %v.", line)
+	}
+}
+
+func TestCatchThrownValueAndRunFinally(t *testing.T) {
+	code := `
+	  var caught;
+	  var steps = "";
+	  try {
+		  steps = steps + "try ";
+		  throw "oops";
+		  steps = steps + "unreachable ";
+	  } catch (e) {
+		  caught = e;
+		  steps = steps + "catch ";
+	  } finally {
+		  steps = steps + "finally";
+	  }
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	caught := interpreter.GetVariableValue("caught").(string)
+	if caught != "oops" {
+		t.Errorf("Expected caught to be %q, but it was %q.", "oops", caught)
+	}
+
+	steps := interpreter.GetVariableValue("steps").(string)
+	if steps != "try catch finally" {
+		t.Errorf("Expected steps to be %q, but it was %q.", "try catch finally", steps)
+	}
+}
+
+func TestFinallyRunsOnBreak(t *testing.T) {
+	code := `
+	  var count = 0;
+	  while (true) {
+		  try {
+			  break;
+		  } finally {
+			  count = count + 1;
+		  }
+	  }
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+	count := interpreter.GetVariableValue("count").(int64)
+
+	if count != 1 {
+		t.Errorf("Expected count to be 1, but it was %v.", count)
+	}
+}
+
+func TestUncaughtThrowIsReported(t *testing.T) {
+	code := `
+	  var finallyRan = false;
+	  try {
+		  throw "bad thing";
+	  } finally {
+		  finallyRan = true;
+	  }
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	if !interpreter.GetVariableValue("finallyRan").(bool) {
+		t.Error("Expected the finally block to run.")
+	}
+
+	assertRuntimeError(t, errorReport, "[line 4] Runtime error: bad thing\n")
+}
+
 func TestClockFunction(t *testing.T) {
 	code := `
 	var result = clock();
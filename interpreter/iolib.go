@@ -0,0 +1,131 @@
+package interpreter
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/maleksiuk/golox/lox"
+)
+
+// WithStdin sets the reader that readLine reads from. The default is os.Stdin.
+func WithStdin(stdin io.Reader) Option {
+	return func(i *Interpreter) {
+		i.stdin = bufio.NewReader(stdin)
+	}
+}
+
+// WithStdout sets the writer that print statements write to. The default is os.Stdout.
+func WithStdout(stdout io.Writer) Option {
+	return func(i *Interpreter) {
+		i.stdout = stdout
+	}
+}
+
+// WithFilesystemAccess controls whether scripts may use the file natives and import modules. It is allowed by
+// default; embeddings that run untrusted scripts can turn it off.
+func WithFilesystemAccess(allowed bool) Option {
+	return func(i *Interpreter) {
+		i.allowFilesystem = allowed
+	}
+}
+
+// checkFilesystemAccess raises an error from the named native if filesystem access is disabled.
+func (i Interpreter) checkFilesystemAccess(name string) {
+	if !i.allowFilesystem {
+		panic(newNativeError("Filesystem access is disabled, so '%v' can't be used.", name))
+	}
+}
+
+// fileFunction wraps a native that needs filesystem access. Errors from the filesystem become runtime errors.
+func fileFunction(name string, arity int, fn func(args []lox.Value) (lox.Value, error)) *nativeFunction {
+	return &nativeFunction{name: name, arity: arity, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		i.checkFilesystemAccess(name)
+
+		result, err := fn(args)
+		if err != nil {
+			panic(newNativeError("%v", err))
+		}
+
+		return result
+	}}
+}
+
+var ioNatives = []*nativeFunction{
+	// readLine returns the next line from stdin without its line ending, or nil at the end of the input.
+	{name: "readLine", arity: 0, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		line, err := i.stdin.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return lox.Nil
+		}
+		if err != nil && err != io.EOF {
+			panic(newNativeError("%v", err))
+		}
+
+		return lox.String(strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"))
+	}},
+
+	fileFunction("readFile", 1, func(args []lox.Value) (lox.Value, error) {
+		buf, err := ioutil.ReadFile(stringArg("readFile", args, 0))
+		if err != nil {
+			return lox.Nil, err
+		}
+
+		return lox.String(string(buf)), nil
+	}),
+
+	fileFunction("writeFile", 2, func(args []lox.Value) (lox.Value, error) {
+		path := stringArg("writeFile", args, 0)
+		contents := stringArg("writeFile", args, 1)
+
+		return lox.Nil, ioutil.WriteFile(path, []byte(contents), 0644)
+	}),
+
+	fileFunction("appendFile", 2, func(args []lox.Value) (lox.Value, error) {
+		path := stringArg("appendFile", args, 0)
+		contents := stringArg("appendFile", args, 1)
+
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return lox.Nil, err
+		}
+
+		if _, err := file.WriteString(contents); err != nil {
+			file.Close()
+			return lox.Nil, err
+		}
+
+		return lox.Nil, file.Close()
+	}),
+
+	// listDir returns the sorted names of the entries in a directory.
+	fileFunction("listDir", 1, func(args []lox.Value) (lox.Value, error) {
+		infos, err := ioutil.ReadDir(stringArg("listDir", args, 0))
+		if err != nil {
+			return lox.Nil, err
+		}
+
+		names := make([]string, len(infos))
+		for idx, info := range infos {
+			names[idx] = info.Name()
+		}
+		sort.Strings(names)
+
+		return newStringList(names), nil
+	}),
+
+	fileFunction("exists", 1, func(args []lox.Value) (lox.Value, error) {
+		_, err := os.Stat(stringArg("exists", args, 0))
+		if os.IsNotExist(err) {
+			return lox.Bool(false), nil
+		}
+		if err != nil {
+			return lox.Nil, err
+		}
+
+		return lox.Bool(true), nil
+	}),
+}
@@ -0,0 +1,97 @@
+package interpreter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadLine(t *testing.T) {
+	code := `
+	  var first = readLine();
+	  var second = readLine();
+	  var third = readLine();
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter(WithStdin(strings.NewReader("hello\r\nworld")))
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{"first": "hello", "second": "world", "third": nil}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestFileFunctions(t *testing.T) {
+	dir := writeScripts(t, map[string]string{"existing.txt": "existing"})
+	defer os.RemoveAll(dir)
+
+	code := `
+	  var dir = "` + filepath.ToSlash(dir) + `";
+	  var path = dir + "/notes.txt";
+	  var existedBefore = exists(path);
+	  writeFile(path, "one");
+	  appendFile(path, " two");
+	  var contents = readFile(path);
+	  var existsAfter = exists(path);
+	  var entries = join(listDir(dir), ",");
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{
+		"existedBefore": false,
+		"contents":      "one two",
+		"existsAfter":   true,
+		"entries":       "existing.txt,notes.txt",
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestReadMissingFileError(t *testing.T) {
+	dir := writeScripts(t, map[string]string{})
+	defer os.RemoveAll(dir)
+
+	code := `readFile("` + filepath.ToSlash(dir) + `/missing.txt");`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	if !errorReport.HadRuntimeError {
+		t.Error("Expected reading a missing file to be a runtime error.")
+	}
+}
+
+func TestFilesystemAccessDisabled(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{`exists("golox.go");`, "[line 1] Runtime error: Filesystem access is disabled, so 'exists' can't be used.\n"},
+		{`import "lib.lox" as lib;`, "[line 1] Runtime error: Filesystem access is disabled, so 'lib.lox' can't be imported.\n"},
+	}
+
+	for _, test := range tests {
+		statements := scanAndParse(test.code)
+
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter(WithFilesystemAccess(false))
+		interpreter.Interpret(statements, &errorReport)
+
+		assertRuntimeError(t, errorReport, test.expected)
+	}
+}
@@ -0,0 +1,212 @@
+package interpreter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/maleksiuk/golox/lox"
+)
+
+// decodeJSON converts JSON text to Lox values: objects become maps, arrays become lists and null becomes nil.
+// Numbers without a fraction or exponent become integers if they fit in one. Errors give the byte offset at which
+// the problem was detected.
+func decodeJSON(text string) lox.Value {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	value := decodeJSONValue(decoder)
+
+	rest := text[decoder.InputOffset():]
+	if trimmed := strings.TrimLeft(rest, " \t\r\n"); trimmed != "" {
+		offset := len(text) - len(trimmed)
+		panic(newNativeError("Invalid JSON at offset %v: unexpected data after value.", offset))
+	}
+
+	return value
+}
+
+func decodeJSONValue(decoder *json.Decoder) lox.Value {
+	token := nextJSONToken(decoder)
+
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '[' {
+			list := &loxList{elements: make([]lox.Value, 0)}
+			for decoder.More() {
+				list.elements = append(list.elements, decodeJSONValue(decoder))
+			}
+			nextJSONToken(decoder)
+
+			return lox.Object(list)
+		}
+
+		if t == '{' {
+			m := newLoxMap()
+			for decoder.More() {
+				key := nextJSONToken(decoder).(string)
+				m.set(lox.String(key), decodeJSONValue(decoder))
+			}
+			nextJSONToken(decoder)
+
+			return lox.Object(m)
+		}
+	case bool:
+		return lox.Bool(t)
+	case json.Number:
+		if num, err := t.Int64(); err == nil {
+			return lox.Int(num)
+		}
+
+		num, err := t.Float64()
+		if err != nil {
+			panic(newNativeError("Invalid JSON number %v: %v.", t, err))
+		}
+		return lox.Float(num)
+	case string:
+		return lox.String(t)
+	}
+
+	return lox.Nil
+}
+
+func nextJSONToken(decoder *json.Decoder) json.Token {
+	token, err := decoder.Token()
+	if err == io.EOF {
+		panic(newNativeError("Invalid JSON at offset %v: unexpected end of JSON input.", decoder.InputOffset()))
+	}
+	if syntaxErr, ok := err.(*json.SyntaxError); ok {
+		panic(newNativeError("Invalid JSON at offset %v: %v.", syntaxErr.Offset, syntaxErr))
+	}
+	if err != nil {
+		panic(newNativeError("Invalid JSON at offset %v: %v.", decoder.InputOffset(), err))
+	}
+
+	return token
+}
+
+// jsonEncoder converts Lox values to JSON. It tracks the lists and maps being encoded so that a value that
+// contains itself is reported rather than recursing forever.
+type jsonEncoder struct {
+	buf    bytes.Buffer
+	active map[interface{}]bool
+}
+
+func encodeJSON(value lox.Value, indent string) string {
+	encoder := jsonEncoder{active: make(map[interface{}]bool)}
+	encoder.encode(value)
+
+	if indent == "" {
+		return encoder.buf.String()
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, encoder.buf.Bytes(), "", indent); err != nil {
+		panic(newNativeError("Could not indent JSON: %v.", err))
+	}
+
+	return indented.String()
+}
+
+func (e *jsonEncoder) encode(value lox.Value) {
+	switch value.Kind() {
+	case lox.NilKind:
+		e.buf.WriteString("null")
+		return
+	case lox.BoolKind, lox.IntKind, lox.StringKind:
+		e.writeScalar(value)
+		return
+	case lox.FloatKind:
+		if num := value.AsNumber(); math.IsNaN(num) || math.IsInf(num, 0) {
+			panic(newNativeError("Cannot convert %v to JSON.", stringify(value)))
+		}
+		e.writeScalar(value)
+		return
+	}
+
+	switch v := value.AsObject().(type) {
+	case *loxList:
+		e.enter(v)
+		e.buf.WriteByte('[')
+		for idx, element := range v.elements {
+			if idx > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.encode(element)
+		}
+		e.buf.WriteByte(']')
+		delete(e.active, v)
+	case *loxMap:
+		e.enter(v)
+		e.buf.WriteByte('{')
+		for idx, key := range v.keys {
+			if !key.IsString() {
+				panic(newNativeError("JSON object keys must be strings, but got %v.", stringify(key)))
+			}
+
+			if idx > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.writeScalar(key)
+			e.buf.WriteByte(':')
+			e.encode(v.values[key])
+		}
+		e.buf.WriteByte('}')
+		delete(e.active, v)
+	default:
+		panic(newNativeError("Cannot convert %v to JSON.", stringify(value)))
+	}
+}
+
+func (e *jsonEncoder) enter(collection interface{}) {
+	if e.active[collection] {
+		panic(newNativeError("Cannot convert a list or map that contains itself to JSON."))
+	}
+	e.active[collection] = true
+}
+
+// writeScalar writes a string, number or boolean. HTML characters are left unescaped since the output isn't
+// necessarily going into a web page.
+func (e *jsonEncoder) writeScalar(value lox.Value) {
+	var scalar bytes.Buffer
+	encoder := json.NewEncoder(&scalar)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value.Interface()); err != nil {
+		panic(newNativeError("Cannot convert %v to JSON.", stringify(value)))
+	}
+
+	e.buf.Write(bytes.TrimSuffix(scalar.Bytes(), []byte("\n")))
+}
+
+// indentArg returns the optional indent argument to jsonStringify, which is either a number of spaces or the
+// string to indent with.
+func indentArg(args []lox.Value, idx int) string {
+	if len(args) <= idx {
+		return ""
+	}
+
+	if args[idx].IsString() {
+		return args[idx].AsString()
+	}
+
+	spaces := integerArg("jsonStringify", args, idx)
+	if spaces < 0 {
+		panic(newNativeError("Argument %v to 'jsonStringify' must not be negative.", idx+1))
+	}
+
+	return strings.Repeat(" ", int(spaces))
+}
+
+var jsonNatives = []*nativeFunction{
+	{name: "jsonParse", arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return decodeJSON(stringArg("jsonParse", args, 0))
+	}},
+
+	// jsonStringify converts a value to JSON, indenting nested values when given a number of spaces or an
+	// indent string.
+	{name: "jsonStringify", arity: 2, optional: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return lox.String(encodeJSON(args[0], indentArg(args, 1)))
+	}},
+}
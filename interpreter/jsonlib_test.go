@@ -0,0 +1,110 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/maleksiuk/golox/lox"
+)
+
+// Lox strings can't contain escaped quotes, so JSON with object keys is built in Go for these tests.
+
+func TestJsonParse(t *testing.T) {
+	value := decodeJSON(`{"name": "lox", "tags": [1, 2.5, true, null], "nested": {"ok": false}}`)
+
+	m, ok := value.AsObject().(*loxMap)
+	if !ok {
+		t.Fatalf("Expected a map, but got %v.", value)
+	}
+	if name, _ := m.get(lox.String("name")); name != lox.String("lox") {
+		t.Errorf("Expected name to be lox, but it was %v.", name)
+	}
+	if printed := stringify(value); printed != "{name: lox, tags: [1, 2.5, true, nil], nested: {ok: false}}" {
+		t.Errorf("Unexpected parsed value %v.", printed)
+	}
+
+	code := `
+	  var list = jsonParse("[1, 2.5, true, null, []]");
+	  var second = list[1];
+	  var flag = list[2];
+	  var null = list[3];
+	  var length = len(list);
+	  var number = jsonParse("-12e2");
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{
+		"second": 2.5,
+		"flag":   true,
+		"null":   nil,
+		"length": int64(5),
+		"number": -1200.0,
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestJsonStringify(t *testing.T) {
+	code := `
+	  var compact = jsonStringify({"a": [1, 2.5, nil], "b": "<q>", "c": true});
+	  var spaces = jsonStringify({"a": [1], "b": {}}, 2);
+	  var tabs = jsonStringify([1], "	");
+	  var scalar = jsonStringify(3);
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{
+		"compact": `{"a":[1,2.5,null],"b":"<q>","c":true}`,
+		"spaces":  "{\n  \"a\": [\n    1\n  ],\n  \"b\": {}\n}",
+		"tabs":    "[\n\t1\n]",
+		"scalar":  "3",
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+
+	text := `{"z":1,"a":[{}],"s":"say \"hi\"\n"}`
+	if roundTrip := encodeJSON(decodeJSON(text), ""); roundTrip != text {
+		t.Errorf("Expected %v to survive a round trip, but got %v.", text, roundTrip)
+	}
+}
+
+func TestJsonErrors(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{`jsonParse("[1, 2");`, "[line 1] Runtime error: Invalid JSON at offset 5: unexpected end of JSON input.\n"},
+		{`jsonParse("[1 2]");`, "[line 1] Runtime error: Invalid JSON at offset 4: invalid character '2' after array element.\n"},
+		{`jsonParse("1 2");`, "[line 1] Runtime error: Invalid JSON at offset 2: unexpected data after value.\n"},
+		{`jsonParse("");`, "[line 1] Runtime error: Invalid JSON at offset 0: unexpected end of JSON input.\n"},
+		{`jsonParse(1);`, "[line 1] Runtime error: Argument 1 to 'jsonParse' must be a string.\n"},
+		{`jsonStringify({1: 2});`, "[line 1] Runtime error: JSON object keys must be strings, but got 1.\n"},
+		{`jsonStringify(clock);`, "[line 1] Runtime error: Cannot convert <native fn> to JSON.\n"},
+		{`var l = [1]; l[0] = l; jsonStringify(l);`, "[line 1] Runtime error: Cannot convert a list or map that contains itself to JSON.\n"},
+		{`jsonStringify(1, -1);`, "[line 1] Runtime error: Argument 2 to 'jsonStringify' must not be negative.\n"},
+		{`jsonStringify();`, "[line 1] Runtime error: Expected 1 to 2 arguments but got 0.\n"},
+	}
+
+	for _, test := range tests {
+		statements := scanAndParse(test.code)
+
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter()
+		interpreter.Interpret(statements, &errorReport)
+
+		assertRuntimeError(t, errorReport, test.expected)
+	}
+}
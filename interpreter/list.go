@@ -0,0 +1,98 @@
+package interpreter
+
+import (
+	"strings"
+
+	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/toks"
+)
+
+// loxList is the value of a list literal such as [1, 2, 3]. Lists are mutable and compared by identity.
+type loxList struct {
+	elements []lox.Value
+}
+
+func (list *loxList) String() string {
+	var str strings.Builder
+
+	str.WriteString("[")
+	for idx, element := range list.elements {
+		if idx > 0 {
+			str.WriteString(", ")
+		}
+		str.WriteString(stringify(element))
+	}
+	str.WriteString("]")
+
+	return str.String()
+}
+
+func (i Interpreter) VisitList(list *expr.List) lox.Value {
+	elements := make([]lox.Value, len(list.Elements))
+	for idx, element := range list.Elements {
+		elements[idx] = i.evaluate(element)
+	}
+
+	return lox.Object(&loxList{elements: elements})
+}
+
+func (i Interpreter) VisitIndex(index *expr.Index) lox.Value {
+	object := i.evaluate(index.Object)
+	key := i.evaluate(index.Index)
+
+	return getIndex(index.Bracket, object, key)
+}
+
+func (i Interpreter) VisitSetIndex(setIndex *expr.SetIndex) lox.Value {
+	object := i.evaluate(setIndex.Object)
+	key := i.evaluate(setIndex.Index)
+	value := i.evaluate(setIndex.Value)
+
+	setIndexValue(setIndex.Bracket, object, key, value)
+	return value
+}
+
+// getIndex returns object[key] for a list, map or string. Strings are indexed by rune. Missing map keys
+// produce nil.
+func getIndex(bracket toks.Token, object lox.Value, key lox.Value) lox.Value {
+	if object.IsString() {
+		runes := []rune(object.AsString())
+		return lox.String(string(runes[checkIndex(bracket, key, len(runes))]))
+	}
+
+	switch o := object.AsObject().(type) {
+	case *loxList:
+		return o.elements[checkIndex(bracket, key, len(o.elements))]
+	case *loxMap:
+		value, _ := o.get(key)
+		return value
+	}
+
+	panic(runtimeError{token: bracket, message: "Can only index lists, maps and strings."})
+}
+
+func setIndexValue(bracket toks.Token, object lox.Value, key lox.Value, value lox.Value) {
+	switch o := object.AsObject().(type) {
+	case *loxList:
+		o.elements[checkIndex(bracket, key, len(o.elements))] = value
+	case *loxMap:
+		o.set(key, value)
+	default:
+		panic(runtimeError{token: bracket, message: "Can only assign to list and map elements."})
+	}
+}
+
+// checkIndex converts key to an index into a sequence of the given length.
+func checkIndex(bracket toks.Token, key lox.Value, length int) int {
+	num, ok := key.ToInt()
+	if !ok {
+		panic(runtimeError{token: bracket, message: "Index must be an integer."})
+	}
+
+	if num < 0 || num >= int64(length) {
+		panic(runtimeError{token: bracket, message: "Index out of range."})
+	}
+
+	return int(num)
+}
@@ -0,0 +1,61 @@
+package interpreter
+
+import (
+	"testing"
+)
+
+func TestLists(t *testing.T) {
+	code := `
+	  var list = [1, "two", [3]];
+	  var first = list[0];
+	  var nested = list[2][0];
+	  list[1] = 2;
+	  var i = 0;
+	  list[i++] += 10;
+	  list[1]++;
+	  var length = len(list);
+	  var printed = str(list);
+	  var empty = str([]);
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{
+		"first":   int64(1),
+		"nested":  int64(3),
+		"i":       int64(1),
+		"length":  int64(3),
+		"printed": "[11, 3, [3]]",
+		"empty":   "[]",
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestListIndexErrors(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{`[1, 2][2];`, "[line 1] Runtime error: Index out of range.\n"},
+		{`[1, 2][0.5];`, "[line 1] Runtime error: Index must be an integer.\n"},
+		{`nil[0];`, "[line 1] Runtime error: Can only index lists, maps and strings.\n"},
+		{`var s = "abc"; s[0] = "x";`, "[line 1] Runtime error: Can only assign to list and map elements.\n"},
+	}
+
+	for _, test := range tests {
+		statements := scanAndParse(test.code)
+
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter()
+		interpreter.Interpret(statements, &errorReport)
+
+		assertRuntimeError(t, errorReport, test.expected)
+	}
+}
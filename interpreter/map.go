@@ -0,0 +1,94 @@
+package interpreter
+
+import (
+	"strings"
+
+	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+)
+
+// loxMap is the value of a map literal such as {"a": 1}. Any Lox value can be a key. Maps remember the order
+// in which keys were first added, so printing them (or converting them to JSON) is deterministic.
+//
+// Since 1 == 1.0, a float with no fractional part is stored as the equivalent integer key.
+type loxMap struct {
+	keys   []lox.Value
+	values map[lox.Value]lox.Value
+}
+
+func newLoxMap() *loxMap {
+	return &loxMap{values: make(map[lox.Value]lox.Value)}
+}
+
+func (m *loxMap) get(key lox.Value) (lox.Value, bool) {
+	value, ok := m.values[mapKey(key)]
+	return value, ok
+}
+
+func (m *loxMap) set(key lox.Value, value lox.Value) {
+	key = mapKey(key)
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func mapKey(key lox.Value) lox.Value {
+	if num, ok := key.ToInt(); ok && key.IsFloat() {
+		return lox.Int(num)
+	}
+
+	return key
+}
+
+func (m *loxMap) String() string {
+	var str strings.Builder
+
+	str.WriteString("{")
+	for idx, key := range m.keys {
+		if idx > 0 {
+			str.WriteString(", ")
+		}
+		str.WriteString(stringify(key))
+		str.WriteString(": ")
+		str.WriteString(stringify(m.values[key]))
+	}
+	str.WriteString("}")
+
+	return str.String()
+}
+
+func (i Interpreter) VisitMap(m *expr.Map) lox.Value {
+	result := newLoxMap()
+	for idx, key := range m.Keys {
+		result.set(i.evaluate(key), i.evaluate(m.Values[idx]))
+	}
+
+	return lox.Object(result)
+}
+
+// mapArg returns the argument at idx, which must be a map.
+func mapArg(name string, args []lox.Value, idx int) *loxMap {
+	m, ok := args[idx].AsObject().(*loxMap)
+	if !ok {
+		panic(newNativeError("Argument %v to '%v' must be a map.", idx+1, name))
+	}
+
+	return m
+}
+
+var mapNatives = []*nativeFunction{
+	// keys returns a new list of a map's keys in the order they were added.
+	{name: "keys", arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		m := mapArg("keys", args, 0)
+
+		keys := make([]lox.Value, len(m.keys))
+		copy(keys, m.keys)
+		return lox.Object(&loxList{elements: keys})
+	}},
+
+	{name: "has", arity: 2, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		_, ok := mapArg("has", args, 0).get(args[1])
+		return lox.Bool(ok)
+	}},
+}
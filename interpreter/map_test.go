@@ -0,0 +1,68 @@
+package interpreter
+
+import (
+	"testing"
+)
+
+func TestMaps(t *testing.T) {
+	code := `
+	  var m = {"a": 1, "b": [2], 3: "three"};
+	  var a = m["a"];
+	  var nested = m["b"][0];
+	  var three = m[3];
+	  var threeFloat = m[3.0];
+	  var missing = m["missing"];
+	  m["a"] += 10;
+	  m["c"] = true;
+	  var length = len(m);
+	  var hasC = has(m, "c");
+	  var hasD = has(m, "d");
+	  var printed = str(m);
+	  var printedKeys = str(keys(m));
+	  var empty = str({});
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{
+		"a":           int64(1),
+		"nested":      int64(2),
+		"three":       "three",
+		"threeFloat":  "three",
+		"missing":     nil,
+		"length":      int64(4),
+		"hasC":        true,
+		"hasD":        false,
+		"printed":     "{a: 11, b: [2], 3: three, c: true}",
+		"printedKeys": "[a, b, 3, c]",
+		"empty":       "{}",
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestMapArgumentErrors(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{`keys([1]);`, "[line 1] Runtime error: Argument 1 to 'keys' must be a map.\n"},
+		{`has("abc", "a");`, "[line 1] Runtime error: Argument 1 to 'has' must be a map.\n"},
+	}
+
+	for _, test := range tests {
+		statements := scanAndParse(test.code)
+
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter()
+		interpreter.Interpret(statements, &errorReport)
+
+		assertRuntimeError(t, errorReport, test.expected)
+	}
+}
@@ -0,0 +1,85 @@
+package interpreter
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/maleksiuk/golox/lox"
+)
+
+// mathFunction1 wraps a Go function of one float64 as a native function.
+func mathFunction1(name string, fn func(float64) float64) *nativeFunction {
+	return &nativeFunction{name: name, arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return lox.Float(fn(numberArg(name, args, 0)))
+	}}
+}
+
+// mathFunction2 wraps a Go function of two float64s as a native function.
+func mathFunction2(name string, fn func(float64, float64) float64) *nativeFunction {
+	return &nativeFunction{name: name, arity: 2, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return lox.Float(fn(numberArg(name, args, 0), numberArg(name, args, 1)))
+	}}
+}
+
+var mathNatives = []*nativeFunction{
+	mathFunction1("floor", math.Floor),
+	mathFunction1("ceil", math.Ceil),
+	mathFunction1("round", math.Round),
+	mathFunction1("abs", math.Abs),
+	mathFunction1("sqrt", math.Sqrt),
+	mathFunction2("pow", math.Pow),
+	mathFunction2("min", math.Min),
+	mathFunction2("max", math.Max),
+	mathFunction1("sin", math.Sin),
+	mathFunction1("cos", math.Cos),
+	mathFunction1("tan", math.Tan),
+	mathFunction1("asin", math.Asin),
+	mathFunction1("acos", math.Acos),
+	mathFunction1("atan", math.Atan),
+	mathFunction2("atan2", math.Atan2),
+	mathFunction1("exp", math.Exp),
+	mathFunction1("log", math.Log),
+	mathFunction1("log10", math.Log10),
+	mathFunction1("log2", math.Log2),
+
+	// random returns a number in [0, 1).
+	{name: "random", arity: 0, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return lox.Float(i.random.Float64())
+	}},
+
+	// randomInt returns an integer in [min, max).
+	{name: "randomInt", arity: 2, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		min := integerArg("randomInt", args, 0)
+		max := integerArg("randomInt", args, 1)
+		if max <= min {
+			panic(newNativeError("The maximum passed to 'randomInt' must be greater than the minimum."))
+		}
+
+		// max-min can overflow an int64, but not a uint64
+		return lox.Int(int64(uint64(min) + randomBelow(i.random, uint64(max)-uint64(min))))
+	}},
+
+	{name: "seedRandom", arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		i.random.Seed(integerArg("seedRandom", args, 0))
+		return lox.Nil
+	}},
+}
+
+// randomBelow returns a random integer in [0, n). n must be greater than zero.
+func randomBelow(random *rand.Rand, n uint64) uint64 {
+	if n <= math.MaxInt64 {
+		return uint64(random.Int63n(int64(n)))
+	}
+
+	// n is more than half of the uint64 range, so each draw is accepted at least half the time
+	for {
+		if v := random.Uint64(); v < n {
+			return v
+		}
+	}
+}
+
+var mathConstants = map[string]lox.Value{
+	"PI": lox.Float(math.Pi),
+	"E":  lox.Float(math.E),
+}
@@ -0,0 +1,118 @@
+package interpreter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/maleksiuk/golox/errorreport"
+)
+
+func TestMathFunctions(t *testing.T) {
+	code := `
+	  var floored = floor(2.7);
+	  var ceiled = ceil(2.1);
+	  var rounded = round(2.5);
+	  var absolute = abs(-3);
+	  var root = sqrt(16);
+	  var power = pow(2, 10);
+	  var smallest = min(3, -1);
+	  var largest = max(3, -1);
+	  var sine = sin(PI / 2);
+	  var logarithm = log(E);
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]float64{
+		"floored":   2,
+		"ceiled":    3,
+		"rounded":   3,
+		"absolute":  3,
+		"root":      4,
+		"power":     1024,
+		"smallest":  -1,
+		"largest":   3,
+		"sine":      1,
+		"logarithm": 1,
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name).(float64); math.Abs(actual-value) > 1e-9 {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestSeededRandomIsDeterministic(t *testing.T) {
+	code := `
+	  var r = random();
+	  var n = randomInt(10, 20);
+	`
+
+	var results [2][2]float64
+	for idx := range results {
+		statements := scanAndParse(code)
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter(WithRandomSeed(42))
+		interpreter.Interpret(statements, &errorReport)
+
+		results[idx][0] = interpreter.GetVariableValue("r").(float64)
+		results[idx][1] = float64(interpreter.GetVariableValue("n").(int64))
+	}
+
+	if results[0] != results[1] {
+		t.Errorf("Expected two interpreters with the same seed to produce the same numbers, but got %v and %v.", results[0], results[1])
+	}
+
+	if r := results[0][0]; r < 0 || r >= 1 {
+		t.Errorf("Expected random() to be in [0, 1), but it was %v.", r)
+	}
+
+	if n := results[0][1]; n < 10 || n >= 20 || n != math.Trunc(n) {
+		t.Errorf("Expected randomInt(10, 20) to be an integer in [10, 20), but it was %v.", n)
+	}
+}
+
+func TestRandomIntWithFullRange(t *testing.T) {
+	code := `
+	  var min = -9223372036854775807 - 1;
+	  var n = randomInt(min, 9223372036854775807);
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter(WithRandomSeed(42))
+	interpreter.Interpret(statements, &errorReport)
+
+	if errorReport.HadRuntimeError {
+		t.Fatalf("Expected no errors but got %v", errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+	}
+	if n := interpreter.GetVariableValue("n").(int64); n == math.MaxInt64 {
+		t.Errorf("Expected randomInt to be less than the maximum, but it was %v.", n)
+	}
+}
+
+func TestMathFunctionArgumentErrors(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{`floor("1");`, "[line 1] Runtime error: Argument 1 to 'floor' must be a number.\n"},
+		{`pow(2, nil);`, "[line 1] Runtime error: Argument 2 to 'pow' must be a number.\n"},
+		{`randomInt(1.5, 3);`, "[line 1] Runtime error: Argument 1 to 'randomInt' must be an integer.\n"},
+		{`randomInt(3, 3);`, "[line 1] Runtime error: The maximum passed to 'randomInt' must be greater than the minimum.\n"},
+		{`sqrt(1, 2);`, "[line 1] Runtime error: Expected 1 arguments but got 2.\n"},
+	}
+
+	for _, test := range tests {
+		statements := scanAndParse(test.code)
+
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter()
+		interpreter.Interpret(statements, &errorReport)
+
+		assertRuntimeError(t, errorReport, test.expected)
+	}
+}
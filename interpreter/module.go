@@ -0,0 +1,170 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/optimizer"
+	"github.com/maleksiuk/golox/parser"
+	"github.com/maleksiuk/golox/scanner"
+	"github.com/maleksiuk/golox/stmt"
+	"github.com/maleksiuk/golox/toks"
+)
+
+// module is the value produced by an import. Its properties are the top-level definitions of the imported file.
+type module struct {
+	name string
+	env  *environment
+}
+
+func (m *module) get(name toks.Token) lox.Value {
+	val, ok := m.env.variables[name.Lexeme]
+	if !ok {
+		message := fmt.Sprintf("Module '%v' has no member '%v'.", m.name, name.Lexeme)
+		panic(runtimeError{token: name, message: message})
+	}
+
+	return val
+}
+
+type script struct {
+	path string // absolute path, used for caching and cycle detection
+	name string // path as written by the user, used in error messages
+}
+
+// moduleLoader keeps track of imported modules. It is shared by every copy of an Interpreter.
+type moduleLoader struct {
+	cache map[string]*module
+
+	// loading is the chain of scripts currently being executed, starting with the main script (if any).
+	loading []script
+}
+
+func newModuleLoader() *moduleLoader {
+	return &moduleLoader{cache: make(map[string]*module)}
+}
+
+// resolve converts an import path to an absolute path. Relative paths are relative to the directory of the
+// importing script, or to the working directory when there is no script (e.g., in the REPL).
+func (loader *moduleLoader) resolve(path string) (string, error) {
+	if !filepath.IsAbs(path) && len(loader.loading) > 0 {
+		importer := loader.loading[len(loader.loading)-1]
+		path = filepath.Join(filepath.Dir(importer.path), path)
+	}
+
+	return filepath.Abs(path)
+}
+
+// cycle returns the chain of script names that ends by importing path again, or nil if path is not being loaded.
+func (loader *moduleLoader) cycle(path string, name string) []string {
+	for idx, s := range loader.loading {
+		if s.path == path {
+			var chain []string
+			for _, loading := range loader.loading[idx:] {
+				chain = append(chain, loading.name)
+			}
+			return append(chain, name)
+		}
+	}
+
+	return nil
+}
+
+// SetScriptPath tells the interpreter which file the statements it executes come from, so that imports can be
+// resolved relative to it and circular imports of the main script can be detected.
+func (i Interpreter) SetScriptPath(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	i.modules.loading = []script{{path: absPath, name: path}}
+	return nil
+}
+
+func (i Interpreter) VisitStatementImport(imp *stmt.Import) {
+	m := i.importModule(imp.Path)
+
+	if imp.Names == nil {
+		i.env.define(imp.Alias.Lexeme, lox.Object(m))
+		return
+	}
+
+	for _, name := range imp.Names {
+		i.env.define(name.Lexeme, m.get(name))
+	}
+}
+
+// importModule scans, parses and executes the file named by pathToken the first time it is imported and
+// returns the cached module after that.
+func (i Interpreter) importModule(pathToken toks.Token) *module {
+	loader := i.modules
+	name := pathToken.Literal.(string)
+
+	if !i.allowFilesystem {
+		panic(runtimeError{token: pathToken, message: fmt.Sprintf("Filesystem access is disabled, so '%v' can't be imported.", name)})
+	}
+
+	path, err := loader.resolve(name)
+	if err != nil {
+		panic(runtimeError{token: pathToken, message: fmt.Sprintf("Could not resolve module '%v': %v", name, err)})
+	}
+
+	if m, ok := loader.cache[path]; ok {
+		return m
+	}
+
+	if chain := loader.cycle(path, name); chain != nil {
+		message := fmt.Sprintf("Circular import: %v.", strings.Join(chain, " -> "))
+		panic(runtimeError{token: pathToken, message: message})
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		panic(runtimeError{token: pathToken, message: fmt.Sprintf("Could not read module '%v': %v", name, err)})
+	}
+
+	loader.loading = append(loader.loading, script{path: path, name: name})
+	defer func() {
+		loader.loading = loader.loading[:len(loader.loading)-1]
+	}()
+
+	// syntax errors are reported with the module's name instead of being mistaken for errors in the importer
+	moduleReport := *i.errorReport
+	moduleReport.File = name
+	moduleReport.HadError = false
+
+	statements := parser.ParseStream(scanner.New(file, &moduleReport), &moduleReport)
+	file.Close()
+	if moduleReport.HadError {
+		i.errorReport.HadError = true
+		panic(runtimeError{token: pathToken, message: fmt.Sprintf("Could not compile module '%v'.", name)})
+	}
+
+	if !resolve(statements, &moduleReport) {
+		i.errorReport.HadError = true
+		panic(runtimeError{token: pathToken, message: fmt.Sprintf("Could not compile module '%v'.", name)})
+	}
+	statements = optimizer.Optimize(statements)
+
+	env := newModuleGlobals(name)
+	moduleState := *i.state
+	moduleState.env = env
+	moduleInterpreter := Interpreter{&moduleState}
+	m := &module{name: name, env: env}
+	m.execute(moduleInterpreter, statements)
+
+	loader.cache[path] = m
+	return m
+}
+
+func (m *module) execute(i Interpreter, statements []stmt.Stmt) {
+	defer locateRuntimeError(m.name)
+
+	for _, statement := range statements {
+		i.execute(statement)
+	}
+}
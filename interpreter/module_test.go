@@ -0,0 +1,176 @@
+package interpreter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/maleksiuk/golox/errorreport"
+)
+
+// writeScripts creates a temporary directory containing the given files and returns its path.
+func writeScripts(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "golox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func interpretScript(t *testing.T, dir string, name string) (Interpreter, errorreport.ErrorReport) {
+	path := filepath.Join(dir, name)
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statements := scanAndParse(string(buf))
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	if err := interpreter.SetScriptPath(path); err != nil {
+		t.Fatal(err)
+	}
+	interpreter.Interpret(statements, &errorReport)
+
+	return interpreter, errorReport
+}
+
+func TestImportModule(t *testing.T) {
+	dir := writeScripts(t, map[string]string{
+		"main.lox": `
+		  import "lib/math.lox" as m;
+		  import "lib/math.lox" as again;
+		  var result = m.answer + m.half;
+		  var sameModule = m == again;
+		`,
+		"lib/math.lox": `
+		  import "constants.lox" as c;
+		  var answer = c.answer;
+		  var half = 0.5;
+		`,
+		"lib/constants.lox": `
+		  var answer = 42;
+		`,
+	})
+	defer os.RemoveAll(dir)
+
+	interpreter, errorReport := interpretScript(t, dir, "main.lox")
+	if errorReport.HadError || errorReport.HadRuntimeError {
+		t.Fatalf("Expected no errors but got %v", errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+	}
+
+	result := interpreter.GetVariableValue("result").(float64)
+	if result != 42.5 {
+		t.Errorf("Expected result to be 42.5, but it was %v.", result)
+	}
+
+	if !interpreter.GetVariableValue("sameModule").(bool) {
+		t.Error("Expected importing the same path twice to produce the same module.")
+	}
+}
+
+func TestFromImport(t *testing.T) {
+	dir := writeScripts(t, map[string]string{
+		"main.lox": `
+		  from "strings.lox" import greeting, name;
+		  var result = greeting + name;
+		`,
+		"strings.lox": `
+		  var greeting = "hello ";
+		  var name = "world";
+		`,
+	})
+	defer os.RemoveAll(dir)
+
+	interpreter, _ := interpretScript(t, dir, "main.lox")
+
+	result := interpreter.GetVariableValue("result").(string)
+	if result != "hello world" {
+		t.Errorf("Expected result to be %q, but it was %q.", "hello world", result)
+	}
+}
+
+func TestCircularImportError(t *testing.T) {
+	dir := writeScripts(t, map[string]string{
+		"main.lox": `import "a.lox" as a;`,
+		"a.lox":    `import "b.lox" as b;`,
+		"b.lox":    `import "a.lox" as a;`,
+	})
+	defer os.RemoveAll(dir)
+
+	_, errorReport := interpretScript(t, dir, "main.lox")
+
+	assertRuntimeError(t, errorReport, "[b.lox, line 1] Runtime error: Circular import: a.lox -> b.lox -> a.lox.\n")
+}
+
+func TestMissingModuleMemberError(t *testing.T) {
+	dir := writeScripts(t, map[string]string{
+		"main.lox":  `from "empty.lox" import nothing;`,
+		"empty.lox": ``,
+	})
+	defer os.RemoveAll(dir)
+
+	_, errorReport := interpretScript(t, dir, "main.lox")
+
+	assertRuntimeError(t, errorReport, "[line 1] Runtime error: Module 'empty.lox' has no member 'nothing'.\n")
+}
+
+func TestNativesAreNotModuleMembers(t *testing.T) {
+	dir := writeScripts(t, map[string]string{
+		"main.lox": `
+		  import "timer.lox" as timer;
+		  var started = timer.started;
+		  from "timer.lox" import clock;
+		`,
+		"timer.lox": `var started = clock() > 0;`,
+	})
+	defer os.RemoveAll(dir)
+
+	interpreter, errorReport := interpretScript(t, dir, "main.lox")
+
+	if !interpreter.GetVariableValue("started").(bool) {
+		t.Error("Expected the module to be able to call clock.")
+	}
+	assertRuntimeError(t, errorReport, "[line 4] Runtime error: Module 'timer.lox' has no member 'clock'.\n")
+}
+
+func TestModuleErrorsNameTheModule(t *testing.T) {
+	dir := writeScripts(t, map[string]string{
+		"syntax.lox":    "import \"broken.lox\" as broken;",
+		"broken.lox":    "var a = 1;\nvar = 2;",
+		"runtime.lox":   "import \"fails.lox\" as fails;",
+		"fails.lox":     "var a = 1;\nprint -\"a\";",
+		"functions.lox": "import \"lib.lox\" as lib;\nlib.check(1);",
+		"lib.lox":       "fun check(n) {\n  return n < \"one\";\n}",
+	})
+	defer os.RemoveAll(dir)
+
+	_, errorReport := interpretScript(t, dir, "syntax.lox")
+	expected := []string{
+		"[broken.lox, line 2] Error at '=': Expect variable name.\n",
+		"[line 1] Runtime error: Could not compile module 'broken.lox'.\n",
+	}
+	if errorMessages := errorReport.Printer.(*errorreport.MockPrinter).GetStrings(); !reflect.DeepEqual(errorMessages, expected) {
+		t.Errorf("Expected errors %q, but got %q.", expected, errorMessages)
+	}
+
+	_, errorReport = interpretScript(t, dir, "runtime.lox")
+	assertRuntimeError(t, errorReport, "[fails.lox, line 2] Runtime error: Operand must be a number.\n")
+
+	_, errorReport = interpretScript(t, dir, "functions.lox")
+	assertRuntimeError(t, errorReport, "[lib.lox, line 2] Runtime error: Operands must be numbers.\n")
+}
@@ -0,0 +1,75 @@
+package interpreter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maleksiuk/golox/lox"
+)
+
+// nativeFunction is a function implemented in Go and made available to Lox code as a global. The last
+// optional parameters may be left out, so fn can receive fewer than arity arguments.
+type nativeFunction struct {
+	name     string
+	arity    int
+	optional int
+	fn       func(i Interpreter, args []lox.Value) lox.Value
+}
+
+func (function *nativeFunction) Call(i Interpreter, args []lox.Value) lox.Value {
+	return function.fn(i, args)
+}
+
+func (function *nativeFunction) Arity() int {
+	return function.arity
+}
+
+func (function *nativeFunction) MinArity() int {
+	return function.arity - function.optional
+}
+
+func (function *nativeFunction) String() string {
+	return "<native fn>"
+}
+
+// nativeError is panicked by native functions that are called incorrectly. VisitCall converts it to a runtime
+// error reported at the call site, since native functions don't know where they were called from.
+type nativeError struct {
+	message string
+}
+
+func newNativeError(format string, a ...interface{}) nativeError {
+	return nativeError{message: fmt.Sprintf(format, a...)}
+}
+
+// numberArg returns the argument at idx, which must be a number.
+func numberArg(name string, args []lox.Value, idx int) float64 {
+	if !args[idx].IsNumber() {
+		panic(newNativeError("Argument %v to '%v' must be a number.", idx+1, name))
+	}
+
+	return args[idx].AsNumber()
+}
+
+// integerArg returns the argument at idx, which must be an integer or a float with no fractional part.
+func integerArg(name string, args []lox.Value, idx int) int64 {
+	num, ok := args[idx].ToInt()
+	if !ok {
+		panic(newNativeError("Argument %v to '%v' must be an integer.", idx+1, name))
+	}
+
+	return num
+}
+
+var coreNatives = []*nativeFunction{
+	{name: "clock", arity: 0, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return lox.Float(float64(time.Now().UnixNano()) / 1e+9)
+	}},
+}
+
+// defineNatives adds the native functions to the environment.
+func defineNatives(env *environment, natives []*nativeFunction) {
+	for _, native := range natives {
+		env.define(native.name, lox.Object(native))
+	}
+}
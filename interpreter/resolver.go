@@ -0,0 +1,309 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/maleksiuk/golox/errorreport"
+	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/stmt"
+	"github.com/maleksiuk/golox/toks"
+)
+
+// resolver works out, before a program runs, which scope each local variable reference refers to. It records
+// the answer in the Local field of expr.Variable and expr.Assign so that the interpreter can index environments
+// directly instead of searching them by name. Variables that aren't found in any enclosing local scope are
+// globals.
+//
+// The scopes the resolver creates must match the environments the interpreter creates: one per block, one per
+// function call holding the parameters and the body's declarations, and one per catch clause holding the error
+// and the clause's declarations.
+type resolver struct {
+	scopes      []map[string]*declaration
+	errorReport *errorreport.ErrorReport
+	hadError    bool
+}
+
+// declaration is a local variable in a scope. defined is false while its initializer is being resolved.
+type declaration struct {
+	slot    int
+	defined bool
+}
+
+// resolve resolves the statements, which are at the top level of a script, and reports whether there were
+// errors.
+func resolve(statements []stmt.Stmt, errorReport *errorreport.ErrorReport) bool {
+	r := &resolver{errorReport: errorReport}
+	r.resolveStatements(statements)
+
+	return !r.hadError
+}
+
+func (r *resolver) resolveStatements(statements []stmt.Stmt) {
+	for _, statement := range statements {
+		statement.Accept(r)
+	}
+}
+
+func (r *resolver) resolveExpression(expression expr.Expr) {
+	expression.Accept(r)
+}
+
+func (r *resolver) beginScope() {
+	r.scopes = append(r.scopes, make(map[string]*declaration))
+}
+
+func (r *resolver) endScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *resolver) declare(name toks.Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+
+	scope := r.scopes[len(r.scopes)-1]
+	if _, ok := scope[name.Lexeme]; ok {
+		r.error(name, "Already a variable with this name in this scope.")
+		return
+	}
+
+	scope[name.Lexeme] = &declaration{slot: len(scope)}
+}
+
+func (r *resolver) define(name toks.Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+
+	if decl, ok := r.scopes[len(r.scopes)-1][name.Lexeme]; ok {
+		decl.defined = true
+	}
+}
+
+// resolveLocal returns where the variable lives, or nil if it is a global.
+func (r *resolver) resolveLocal(name toks.Token) *expr.Local {
+	for idx := len(r.scopes) - 1; idx >= 0; idx-- {
+		if decl, ok := r.scopes[idx][name.Lexeme]; ok {
+			return &expr.Local{Depth: len(r.scopes) - 1 - idx, Slot: decl.slot}
+		}
+	}
+
+	return nil
+}
+
+func (r *resolver) resolveFunction(params []toks.Token, body []stmt.Stmt) {
+	r.beginScope()
+	for _, param := range params {
+		r.declare(param)
+		r.define(param)
+	}
+	r.resolveStatements(body)
+	r.endScope()
+}
+
+func (r *resolver) error(token toks.Token, message string) {
+	r.hadError = true
+	r.errorReport.Report(token.Line, fmt.Sprintf("at '%v'", token.Lexeme), message)
+}
+
+func (r *resolver) VisitStatementExpression(expression *stmt.Expression) {
+	r.resolveExpression(expression.Expression)
+}
+
+func (r *resolver) VisitStatementPrint(p *stmt.Print) {
+	r.resolveExpression(p.Expression)
+}
+
+func (r *resolver) VisitStatementVar(v *stmt.Var) {
+	r.declare(v.Name)
+	if v.Initializer != nil {
+		r.resolveExpression(v.Initializer)
+	}
+	r.define(v.Name)
+}
+
+func (r *resolver) VisitStatementBlock(block *stmt.Block) {
+	r.beginScope()
+	r.resolveStatements(block.Statements)
+	r.endScope()
+}
+
+func (r *resolver) VisitStatementConditional(conditional *stmt.Conditional) {
+	r.resolveExpression(conditional.Condition)
+	conditional.ThenStatement.Accept(r)
+	if conditional.ElseStatement != nil {
+		conditional.ElseStatement.Accept(r)
+	}
+}
+
+func (r *resolver) VisitStatementWhile(while *stmt.While) {
+	r.resolveExpression(while.Condition)
+	while.Body.Accept(r)
+	if while.Increment != nil {
+		r.resolveExpression(while.Increment)
+	}
+}
+
+func (r *resolver) VisitStatementFunction(function *stmt.Function) {
+	// The function is defined before its body is resolved so that it can call itself.
+	r.declare(function.Name)
+	r.define(function.Name)
+	r.resolveFunction(function.Params, function.Body)
+}
+
+func (r *resolver) VisitStatementBreak(b *stmt.Break) {
+}
+
+func (r *resolver) VisitStatementContinue(c *stmt.Continue) {
+}
+
+func (r *resolver) VisitStatementThrow(throw *stmt.Throw) {
+	r.resolveExpression(throw.Value)
+}
+
+func (r *resolver) VisitStatementTry(try *stmt.Try) {
+	r.beginScope()
+	r.resolveStatements(try.Body)
+	r.endScope()
+
+	if try.CatchBody != nil {
+		r.beginScope()
+		r.declare(try.CatchParam)
+		r.define(try.CatchParam)
+		r.resolveStatements(try.CatchBody)
+		r.endScope()
+	}
+
+	if try.FinallyBody != nil {
+		r.beginScope()
+		r.resolveStatements(try.FinallyBody)
+		r.endScope()
+	}
+}
+
+func (r *resolver) VisitStatementImport(imp *stmt.Import) {
+	if imp.Names == nil {
+		r.declare(imp.Alias)
+		r.define(imp.Alias)
+		return
+	}
+
+	for _, name := range imp.Names {
+		r.declare(name)
+		r.define(name)
+	}
+}
+
+func (r *resolver) VisitStatementReturn(ret *stmt.Return) {
+	if ret.Value != nil {
+		r.resolveExpression(ret.Value)
+	}
+}
+
+func (r *resolver) VisitBinary(binary *expr.Binary) lox.Value {
+	r.resolveExpression(binary.Left)
+	r.resolveExpression(binary.Right)
+	return lox.Nil
+}
+
+func (r *resolver) VisitLogical(logical *expr.Logical) lox.Value {
+	r.resolveExpression(logical.Left)
+	r.resolveExpression(logical.Right)
+	return lox.Nil
+}
+
+func (r *resolver) VisitConditional(conditional *expr.Conditional) lox.Value {
+	r.resolveExpression(conditional.Condition)
+	r.resolveExpression(conditional.Then)
+	r.resolveExpression(conditional.Else)
+	return lox.Nil
+}
+
+func (r *resolver) VisitGrouping(grouping *expr.Grouping) lox.Value {
+	r.resolveExpression(grouping.Expression)
+	return lox.Nil
+}
+
+func (r *resolver) VisitLiteral(literal *expr.Literal) lox.Value {
+	return lox.Nil
+}
+
+func (r *resolver) VisitUnary(unary *expr.Unary) lox.Value {
+	r.resolveExpression(unary.Right)
+	return lox.Nil
+}
+
+func (r *resolver) VisitVariable(variable *expr.Variable) lox.Value {
+	if len(r.scopes) > 0 {
+		if decl, ok := r.scopes[len(r.scopes)-1][variable.Name.Lexeme]; ok && !decl.defined {
+			r.error(variable.Name, "Can't read local variable in its own initializer.")
+		}
+	}
+
+	variable.Local = r.resolveLocal(variable.Name)
+	return lox.Nil
+}
+
+func (r *resolver) VisitAssign(assign *expr.Assign) lox.Value {
+	r.resolveExpression(assign.Value)
+	assign.Local = r.resolveLocal(assign.Name)
+	return lox.Nil
+}
+
+func (r *resolver) VisitCompoundAssign(compoundAssign *expr.CompoundAssign) lox.Value {
+	r.resolveExpression(compoundAssign.Target)
+	r.resolveExpression(compoundAssign.Value)
+	return lox.Nil
+}
+
+func (r *resolver) VisitUpdate(update *expr.Update) lox.Value {
+	r.resolveExpression(update.Target)
+	return lox.Nil
+}
+
+func (r *resolver) VisitCall(call *expr.Call) lox.Value {
+	r.resolveExpression(call.Callee)
+	for _, arg := range call.Arguments {
+		r.resolveExpression(arg)
+	}
+	return lox.Nil
+}
+
+func (r *resolver) VisitGet(get *expr.Get) lox.Value {
+	r.resolveExpression(get.Object)
+	return lox.Nil
+}
+
+func (r *resolver) VisitFunction(function *expr.Function) lox.Value {
+	r.resolveFunction(function.Params, stmt.FunctionBody(function))
+	return lox.Nil
+}
+
+func (r *resolver) VisitList(list *expr.List) lox.Value {
+	for _, element := range list.Elements {
+		r.resolveExpression(element)
+	}
+	return lox.Nil
+}
+
+func (r *resolver) VisitMap(m *expr.Map) lox.Value {
+	for idx, key := range m.Keys {
+		r.resolveExpression(key)
+		r.resolveExpression(m.Values[idx])
+	}
+	return lox.Nil
+}
+
+func (r *resolver) VisitIndex(index *expr.Index) lox.Value {
+	r.resolveExpression(index.Object)
+	r.resolveExpression(index.Index)
+	return lox.Nil
+}
+
+func (r *resolver) VisitSetIndex(setIndex *expr.SetIndex) lox.Value {
+	r.resolveExpression(setIndex.Object)
+	r.resolveExpression(setIndex.Index)
+	r.resolveExpression(setIndex.Value)
+	return lox.Nil
+}
@@ -0,0 +1,104 @@
+package interpreter
+
+import (
+	"testing"
+
+	"github.com/maleksiuk/golox/errorreport"
+)
+
+func TestResolveLocals(t *testing.T) {
+	code := `
+	  var a = "global";
+	  var early;
+	  var shadowed;
+	  var caught;
+	  var total = 0;
+	  {
+	    fun show() { return a; }
+	    fun assign() { a = "assigned"; }
+	    var a = "local";
+	    early = show();
+	    assign();
+	    shadowed = a;
+	  }
+
+	  fun makeCounter() {
+	    var count = 0;
+	    return () => count += 1;
+	  }
+	  var counter = makeCounter();
+	  counter();
+	  var counted = counter();
+
+	  try {
+	    var x = 1;
+	    throw "oops";
+	  } catch (e) {
+	    var y = e + "!";
+	    caught = y;
+	  }
+
+	  for (var i = 0; i < 4; i++) {
+	    if (i == 1) continue;
+	    var squared = i * i;
+	    total += squared;
+	  }
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{
+		"a":        "assigned",
+		"early":    "global",
+		"shadowed": "local",
+		"counted":  int64(2),
+		"caught":   "oops!",
+		"total":    int64(13),
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{`{ var a = 1; var a = 2; }`, "[line 1] Error at 'a': Already a variable with this name in this scope.\n"},
+		{`fun f(a, a) {}`, "[line 1] Error at 'a': Already a variable with this name in this scope.\n"},
+		{`{ var a = a; }`, "[line 1] Error at 'a': Can't read local variable in its own initializer.\n"},
+		// the optimizer removes this branch, but the program is resolved before that
+		{`if (false) { var a = 1; { var b = b; } }`, "[line 1] Error at 'b': Can't read local variable in its own initializer.\n"},
+	}
+
+	for _, test := range tests {
+		statements := scanAndParse(test.code)
+
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter()
+		interpreter.Interpret(statements, &errorReport)
+
+		errorMessages := errorReport.Printer.(*errorreport.MockPrinter).GetStrings()
+		if !errorReport.HadError || len(errorMessages) != 1 || errorMessages[0] != test.expected {
+			t.Errorf("Expected a single error %q, but got %q.", test.expected, errorMessages)
+		}
+	}
+}
+
+func TestRedeclareGlobal(t *testing.T) {
+	statements := scanAndParse(`var a = 1; var a = a + 1;`)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	if a := interpreter.GetVariableValue("a"); a != int64(2) {
+		t.Errorf("Expected a to be 2, but it was %v.", a)
+	}
+}
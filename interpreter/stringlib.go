@@ -0,0 +1,154 @@
+package interpreter
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/scanner"
+)
+
+// stringArg returns the argument at idx, which must be a string.
+func stringArg(name string, args []lox.Value, idx int) string {
+	if !args[idx].IsString() {
+		panic(newNativeError("Argument %v to '%v' must be a string.", idx+1, name))
+	}
+
+	return args[idx].AsString()
+}
+
+// listArg returns the argument at idx, which must be a list.
+func listArg(name string, args []lox.Value, idx int) *loxList {
+	list, ok := args[idx].AsObject().(*loxList)
+	if !ok {
+		panic(newNativeError("Argument %v to '%v' must be a list.", idx+1, name))
+	}
+
+	return list
+}
+
+// stringFunction1 wraps a Go function of one string as a native function.
+func stringFunction1(name string, fn func(string) string) *nativeFunction {
+	return &nativeFunction{name: name, arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return lox.String(fn(stringArg(name, args, 0)))
+	}}
+}
+
+// stringPredicate wraps a Go function of two strings that returns a bool as a native function.
+func stringPredicate(name string, fn func(string, string) bool) *nativeFunction {
+	return &nativeFunction{name: name, arity: 2, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return lox.Bool(fn(stringArg(name, args, 0), stringArg(name, args, 1)))
+	}}
+}
+
+// newStringList converts a slice of Go strings to a Lox list.
+func newStringList(strs []string) lox.Value {
+	elements := make([]lox.Value, len(strs))
+	for idx, str := range strs {
+		elements[idx] = lox.String(str)
+	}
+
+	return lox.Object(&loxList{elements: elements})
+}
+
+// String positions are counted in runes rather than bytes, to match the way the scanner reads source code.
+var stringNatives = []*nativeFunction{
+	// len returns the number of runes in a string or the number of elements in a list or map.
+	{name: "len", arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		if args[0].IsString() {
+			return lox.Int(int64(utf8.RuneCountInString(args[0].AsString())))
+		}
+
+		switch v := args[0].AsObject().(type) {
+		case *loxList:
+			return lox.Int(int64(len(v.elements)))
+		case *loxMap:
+			return lox.Int(int64(len(v.keys)))
+		}
+
+		panic(newNativeError("Argument 1 to 'len' must be a string, list or map."))
+	}},
+
+	// substr returns the runes of s in [start, end).
+	{name: "substr", arity: 3, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		runes := []rune(stringArg("substr", args, 0))
+		start := integerArg("substr", args, 1)
+		end := integerArg("substr", args, 2)
+		if start < 0 || end > int64(len(runes)) || start > end {
+			panic(newNativeError("Substring range [%v, %v) is out of range for a string of length %v.", start, end, len(runes)))
+		}
+
+		return lox.String(string(runes[start:end]))
+	}},
+
+	// indexOf returns the rune index of the first occurrence of sub in s, or -1 if there isn't one.
+	{name: "indexOf", arity: 2, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		s := stringArg("indexOf", args, 0)
+		byteIndex := strings.Index(s, stringArg("indexOf", args, 1))
+		if byteIndex < 0 {
+			return lox.Int(-1)
+		}
+
+		return lox.Int(int64(utf8.RuneCountInString(s[:byteIndex])))
+	}},
+
+	// split splits s around each occurrence of sep. An empty sep splits s into runes.
+	{name: "split", arity: 2, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return newStringList(strings.Split(stringArg("split", args, 0), stringArg("split", args, 1)))
+	}},
+
+	// join concatenates the elements of a list, which may be of any type, with sep between them.
+	{name: "join", arity: 2, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		list := listArg("join", args, 0)
+		sep := stringArg("join", args, 1)
+
+		strs := make([]string, len(list.elements))
+		for idx, element := range list.elements {
+			strs[idx] = stringify(element)
+		}
+
+		return lox.String(strings.Join(strs, sep))
+	}},
+
+	stringFunction1("upper", strings.ToUpper),
+	stringFunction1("lower", strings.ToLower),
+	stringFunction1("trim", strings.TrimSpace),
+
+	// replace replaces every occurrence of old in s with new.
+	{name: "replace", arity: 3, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		s := stringArg("replace", args, 0)
+		return lox.String(strings.ReplaceAll(s, stringArg("replace", args, 1), stringArg("replace", args, 2)))
+	}},
+
+	stringPredicate("startsWith", strings.HasPrefix),
+	stringPredicate("endsWith", strings.HasSuffix),
+	stringPredicate("contains", strings.Contains),
+
+	// chars returns a list of the runes in s, each as a one-character string.
+	{name: "chars", arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return newStringList(strings.Split(stringArg("chars", args, 0), ""))
+	}},
+
+	// str converts any value to a string, the same way print does.
+	{name: "str", arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return lox.String(stringify(args[0]))
+	}},
+
+	// num converts a string to a number using the rules for number literals, with an optional leading '-'.
+	// It returns nil if the string is not a number.
+	{name: "num", arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		s := stringArg("num", args, 0)
+
+		negative := strings.HasPrefix(s, "-")
+		num, ok := scanner.ParseNumber(strings.TrimPrefix(s, "-"))
+		if !ok {
+			return lox.Nil
+		}
+
+		if negative {
+			// A positive integer literal is at most math.MaxInt64, so negating it can't overflow.
+			num, _ = lox.Negate(num)
+		}
+		return num
+	}},
+}
@@ -0,0 +1,76 @@
+package interpreter
+
+import (
+	"testing"
+)
+
+func TestStringFunctions(t *testing.T) {
+	code := `
+	  var greeting = "  Grüße, Welt  ";
+	  var trimmed = trim(greeting);
+	  var length = len(trimmed);
+	  var sub = substr(trimmed, 0, 5);
+	  var index = indexOf(trimmed, "Welt");
+	  var missing = indexOf(trimmed, "xyz");
+	  var shouted = upper(trimmed);
+	  var quiet = lower(trimmed);
+	  var replaced = replace("a-b-c", "-", "+");
+	  var starts = startsWith(trimmed, "Grü");
+	  var joined = join(split("a,b,c", ","), ";");
+	  var letters = join(chars("äbc"), " ");
+	  var converted = str(12.5) + str(nil) + str(true);
+	  var parsed = num("12.5") + num("-2");
+	  var invalid = num("1.2.3");
+	  var third = "äbc"[2];
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{
+		"trimmed":   "Grüße, Welt",
+		"length":    int64(11),
+		"sub":       "Grüße",
+		"index":     int64(7),
+		"missing":   int64(-1),
+		"shouted":   "GRÜßE, WELT",
+		"quiet":     "grüße, welt",
+		"replaced":  "a+b+c",
+		"starts":    true,
+		"joined":    "a;b;c",
+		"letters":   "ä b c",
+		"converted": "12.5niltrue",
+		"parsed":    10.5,
+		"invalid":   nil,
+		"third":     "c",
+	}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestStringFunctionArgumentErrors(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{`upper(1);`, "[line 1] Runtime error: Argument 1 to 'upper' must be a string.\n"},
+		{`len(nil);`, "[line 1] Runtime error: Argument 1 to 'len' must be a string, list or map.\n"},
+		{`substr("abc", 2, 4);`, "[line 1] Runtime error: Substring range [2, 4) is out of range for a string of length 3.\n"},
+		{`join("abc", "");`, "[line 1] Runtime error: Argument 1 to 'join' must be a list.\n"},
+	}
+
+	for _, test := range tests {
+		statements := scanAndParse(test.code)
+
+		errorReport := newMockErrorReport()
+		interpreter := NewInterpreter()
+		interpreter.Interpret(statements, &errorReport)
+
+		assertRuntimeError(t, errorReport, test.expected)
+	}
+}
@@ -0,0 +1,57 @@
+package interpreter
+
+import (
+	"os"
+
+	"github.com/maleksiuk/golox/lox"
+)
+
+// exitSignal is panicked by the exit native and recovered by Interpret, which stops executing the program.
+type exitSignal struct {
+	code int
+}
+
+// exitStatus records whether the program called exit. It is shared by every copy of an Interpreter.
+type exitStatus struct {
+	exited bool
+	code   int
+}
+
+// WithArgs sets the command-line arguments that the args native returns.
+func WithArgs(args []string) Option {
+	return func(i *Interpreter) {
+		i.args = args
+	}
+}
+
+// ExitCode returns the status passed to the exit native and true if the program called it.
+func (i Interpreter) ExitCode() (int, bool) {
+	return i.exit.code, i.exit.exited
+}
+
+var systemNatives = []*nativeFunction{
+	// args returns a new list of the script's command-line arguments.
+	{name: "args", arity: 0, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		return newStringList(i.args)
+	}},
+
+	// env returns the value of an environment variable, or nil if it isn't set.
+	{name: "env", arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		value, ok := os.LookupEnv(stringArg("env", args, 0))
+		if !ok {
+			return lox.Nil
+		}
+
+		return lox.String(value)
+	}},
+
+	// exit stops the program. The code becomes the exit status of the golox process.
+	{name: "exit", arity: 1, fn: func(i Interpreter, args []lox.Value) lox.Value {
+		code := integerArg("exit", args, 0)
+		if code < 0 || code > 255 {
+			panic(newNativeError("Exit code must be between 0 and 255."))
+		}
+
+		panic(exitSignal{code: int(code)})
+	}},
+}
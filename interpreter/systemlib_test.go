@@ -0,0 +1,66 @@
+package interpreter
+
+import (
+	"os"
+	"testing"
+)
+
+func TestArgsAndEnv(t *testing.T) {
+	os.Setenv("GOLOX_TEST_VARIABLE", "set")
+	defer os.Unsetenv("GOLOX_TEST_VARIABLE")
+
+	code := `
+	  var arguments = args();
+	  var count = len(arguments);
+	  var second = arguments[1];
+	  var variable = env("GOLOX_TEST_VARIABLE");
+	  var missing = env("GOLOX_TEST_MISSING_VARIABLE");
+	`
+	statements := scanAndParse(code)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter(WithArgs([]string{"one", "two"}))
+	interpreter.Interpret(statements, &errorReport)
+
+	expected := map[string]interface{}{"count": int64(2), "second": "two", "variable": "set", "missing": nil}
+	for name, value := range expected {
+		if actual := interpreter.GetVariableValue(name); actual != value {
+			t.Errorf("Expected %v to be %v, but it was %v.", name, value, actual)
+		}
+	}
+}
+
+func TestExit(t *testing.T) {
+	source := `
+	  var reached = "before";
+	  try {
+		  exit(3);
+	  } catch (e) {
+		  reached = "catch";
+	  }
+	  reached = "after";
+	`
+	statements := scanAndParse(source)
+
+	errorReport := newMockErrorReport()
+	interpreter := NewInterpreter()
+
+	if _, exited := interpreter.ExitCode(); exited {
+		t.Error("Expected the interpreter not to have exited before running anything.")
+	}
+
+	interpreter.Interpret(statements, &errorReport)
+
+	code, exited := interpreter.ExitCode()
+	if !exited || code != 3 {
+		t.Errorf("Expected the program to exit with code 3, but got %v, %v.", code, exited)
+	}
+
+	if reached := interpreter.GetVariableValue("reached"); reached != "before" {
+		t.Errorf("Expected execution to stop at exit, but reached was %v.", reached)
+	}
+
+	if errorReport.HadRuntimeError {
+		t.Error("Expected exit not to be reported as a runtime error.")
+	}
+}
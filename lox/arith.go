@@ -0,0 +1,223 @@
+package lox
+
+import (
+	"errors"
+	"math"
+)
+
+// The arithmetic functions take operands that must be numbers. An operation on two integers produces an integer,
+// and reports ErrIntegerOverflow rather than wrapping around. If either operand is a float, both are converted
+// to floats and so is the result.
+
+var (
+	ErrIntegerOverflow = errors.New("Integer overflow.")
+	ErrDivisionByZero  = errors.New("Integer division by zero.")
+	ErrNegativeShift   = errors.New("Shift count must not be negative.")
+)
+
+func Add(a Value, b Value) (Value, error) {
+	if a.IsInt() && b.IsInt() {
+		sum := a.AsInt() + b.AsInt()
+		if (a.AsInt()^sum)&(b.AsInt()^sum) < 0 {
+			return Nil, ErrIntegerOverflow
+		}
+		return Int(sum), nil
+	}
+
+	return Float(a.AsNumber() + b.AsNumber()), nil
+}
+
+func Subtract(a Value, b Value) (Value, error) {
+	if a.IsInt() && b.IsInt() {
+		difference := a.AsInt() - b.AsInt()
+		if (a.AsInt()^b.AsInt())&(a.AsInt()^difference) < 0 {
+			return Nil, ErrIntegerOverflow
+		}
+		return Int(difference), nil
+	}
+
+	return Float(a.AsNumber() - b.AsNumber()), nil
+}
+
+func Multiply(a Value, b Value) (Value, error) {
+	if a.IsInt() && b.IsInt() {
+		product, ok := multiplyInts(a.AsInt(), b.AsInt())
+		if !ok {
+			return Nil, ErrIntegerOverflow
+		}
+		return Int(product), nil
+	}
+
+	return Float(a.AsNumber() * b.AsNumber()), nil
+}
+
+func multiplyInts(a int64, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+
+	return product, true
+}
+
+// Divide always produces a float, so 1 / 2 is 0.5 as it is in jlox. Use FloorDivide for integer division.
+func Divide(a Value, b Value) Value {
+	return Float(a.AsNumber() / b.AsNumber())
+}
+
+// FloorDivide divides and rounds the result down, so -7 ~/ 2 is -4.
+func FloorDivide(a Value, b Value) (Value, error) {
+	if a.IsInt() && b.IsInt() {
+		x, y := a.AsInt(), b.AsInt()
+		if y == 0 {
+			return Nil, ErrDivisionByZero
+		}
+		if x == math.MinInt64 && y == -1 {
+			return Nil, ErrIntegerOverflow
+		}
+
+		quotient := x / y
+		if x%y != 0 && (x < 0) != (y < 0) {
+			quotient--
+		}
+		return Int(quotient), nil
+	}
+
+	return Float(math.Floor(a.AsNumber() / b.AsNumber())), nil
+}
+
+// Modulo returns the remainder of truncated division, which has the same sign as a.
+func Modulo(a Value, b Value) (Value, error) {
+	if a.IsInt() && b.IsInt() {
+		if b.AsInt() == 0 {
+			return Nil, ErrDivisionByZero
+		}
+		return Int(a.AsInt() % b.AsInt()), nil
+	}
+
+	return Float(math.Mod(a.AsNumber(), b.AsNumber())), nil
+}
+
+// Power raises a to the power b. An integer raised to a negative integer power is a float.
+func Power(a Value, b Value) (Value, error) {
+	if a.IsInt() && b.IsInt() && b.AsInt() >= 0 {
+		result, base, exponent := int64(1), a.AsInt(), b.AsInt()
+		for exponent > 0 {
+			var ok bool
+			if exponent&1 == 1 {
+				if result, ok = multiplyInts(result, base); !ok {
+					return Nil, ErrIntegerOverflow
+				}
+			}
+
+			exponent >>= 1
+			if exponent > 0 {
+				if base, ok = multiplyInts(base, base); !ok {
+					return Nil, ErrIntegerOverflow
+				}
+			}
+		}
+		return Int(result), nil
+	}
+
+	return Float(math.Pow(a.AsNumber(), b.AsNumber())), nil
+}
+
+func Negate(a Value) (Value, error) {
+	if a.IsInt() {
+		if a.AsInt() == math.MinInt64 {
+			return Nil, ErrIntegerOverflow
+		}
+		return Int(-a.AsInt()), nil
+	}
+
+	return Float(-a.AsNumber()), nil
+}
+
+// The bitwise functions take operands that must be integers or floats with no fractional part (see ToInt), and
+// produce integers.
+
+func BitwiseAnd(a Value, b Value) Value {
+	x, _ := a.ToInt()
+	y, _ := b.ToInt()
+	return Int(x & y)
+}
+
+func BitwiseOr(a Value, b Value) Value {
+	x, _ := a.ToInt()
+	y, _ := b.ToInt()
+	return Int(x | y)
+}
+
+func BitwiseXor(a Value, b Value) Value {
+	x, _ := a.ToInt()
+	y, _ := b.ToInt()
+	return Int(x ^ y)
+}
+
+func BitwiseNot(a Value) Value {
+	x, _ := a.ToInt()
+	return Int(^x)
+}
+
+// ShiftLeft shifts a left by b bits. As in C, bits shifted past the sign bit are lost rather than reported as an
+// overflow.
+func ShiftLeft(a Value, b Value) (Value, error) {
+	x, _ := a.ToInt()
+	n, _ := b.ToInt()
+	if n < 0 {
+		return Nil, ErrNegativeShift
+	}
+
+	return Int(x << uint64(n)), nil
+}
+
+// ShiftRight shifts a right by b bits, copying the sign bit, so -8 >> 1 is -4.
+func ShiftRight(a Value, b Value) (Value, error) {
+	x, _ := a.ToInt()
+	n, _ := b.ToInt()
+	if n < 0 {
+		return Nil, ErrNegativeShift
+	}
+
+	return Int(x >> uint64(n)), nil
+}
+
+// Less reports whether a < b. Integers are compared exactly rather than being converted to floats.
+func Less(a Value, b Value) bool {
+	if a.IsInt() && b.IsInt() {
+		return a.AsInt() < b.AsInt()
+	}
+
+	return a.AsNumber() < b.AsNumber()
+}
+
+// LessEqual reports whether a <= b.
+func LessEqual(a Value, b Value) bool {
+	if a.IsInt() && b.IsInt() {
+		return a.AsInt() <= b.AsInt()
+	}
+
+	return a.AsNumber() <= b.AsNumber()
+}
+
+// numbersEqual compares two numbers exactly, so a large integer isn't equal to a float that it only rounds to.
+func numbersEqual(a Value, b Value) bool {
+	if a.IsInt() && b.IsInt() {
+		return a.AsInt() == b.AsInt()
+	}
+
+	if a.IsInt() {
+		a, b = b, a
+	}
+	if b.IsInt() {
+		num, ok := a.ToInt()
+		return ok && num == b.AsInt()
+	}
+
+	return a.AsNumber() == b.AsNumber()
+}
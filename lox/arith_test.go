@@ -0,0 +1,99 @@
+package lox
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegerArithmetic(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func(Value, Value) (Value, error)
+		a        int64
+		b        int64
+		expected int64
+	}{
+		{"Add", Add, 2, 3, 5},
+		{"Subtract", Subtract, 2, 3, -1},
+		{"Multiply", Multiply, -4, 3, -12},
+		{"FloorDivide", FloorDivide, 7, 2, 3},
+		{"FloorDivide", FloorDivide, -7, 2, -4},
+		{"FloorDivide", FloorDivide, 7, -2, -4},
+		{"FloorDivide", FloorDivide, -8, 2, -4},
+		{"Modulo", Modulo, -7, 3, -1},
+		{"Power", Power, 3, 4, 81},
+		{"Power", Power, -2, 63, math.MinInt64},
+	}
+
+	for _, test := range tests {
+		result, err := test.fn(Int(test.a), Int(test.b))
+		if err != nil || result != Int(test.expected) {
+			t.Errorf("%v(%v, %v) = %v, %v, want %v", test.name, test.a, test.b, result, err, test.expected)
+		}
+	}
+}
+
+func TestIntegerArithmeticErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func(Value, Value) (Value, error)
+		a        int64
+		b        int64
+		expected error
+	}{
+		{"Add", Add, math.MaxInt64, 1, ErrIntegerOverflow},
+		{"Subtract", Subtract, math.MinInt64, 1, ErrIntegerOverflow},
+		{"Multiply", Multiply, math.MinInt64, -1, ErrIntegerOverflow},
+		{"Multiply", Multiply, 1 << 32, 1 << 31, ErrIntegerOverflow},
+		{"FloorDivide", FloorDivide, math.MinInt64, -1, ErrIntegerOverflow},
+		{"FloorDivide", FloorDivide, 1, 0, ErrDivisionByZero},
+		{"Modulo", Modulo, 1, 0, ErrDivisionByZero},
+		{"Power", Power, 2, 63, ErrIntegerOverflow},
+	}
+
+	for _, test := range tests {
+		if _, err := test.fn(Int(test.a), Int(test.b)); err != test.expected {
+			t.Errorf("%v(%v, %v) returned error %v, want %v", test.name, test.a, test.b, err, test.expected)
+		}
+	}
+
+	if _, err := Negate(Int(math.MinInt64)); err != ErrIntegerOverflow {
+		t.Errorf("Negate(%v) returned error %v, want %v", int64(math.MinInt64), err, ErrIntegerOverflow)
+	}
+}
+
+func TestMixedArithmetic(t *testing.T) {
+	if result, _ := Add(Int(1), Float(0.5)); result != Float(1.5) {
+		t.Errorf("Add(1, 0.5) = %v, want 1.5", result)
+	}
+
+	if result := Divide(Int(1), Int(2)); result != Float(0.5) {
+		t.Errorf("Divide(1, 2) = %v, want 0.5", result)
+	}
+
+	if result, _ := Power(Int(2), Int(-2)); result != Float(0.25) {
+		t.Errorf("Power(2, -2) = %v, want 0.25", result)
+	}
+
+	if result, _ := FloorDivide(Float(-7), Int(2)); result != Float(-4) {
+		t.Errorf("FloorDivide(-7.0, 2) = %v, want -4.0", result)
+	}
+}
+
+func TestBitwise(t *testing.T) {
+	if result := BitwiseAnd(Int(0b1100), Float(10)); result != Int(0b1000) {
+		t.Errorf("BitwiseAnd(12, 10.0) = %v, want 8", result)
+	}
+
+	if result, _ := ShiftRight(Int(-8), Int(1)); result != Int(-4) {
+		t.Errorf("ShiftRight(-8, 1) = %v, want -4", result)
+	}
+
+	if result, _ := ShiftLeft(Int(1), Int(64)); result != Int(0) {
+		t.Errorf("ShiftLeft(1, 64) = %v, want 0", result)
+	}
+
+	if _, err := ShiftLeft(Int(1), Int(-1)); err != ErrNegativeShift {
+		t.Errorf("ShiftLeft(1, -1) returned error %v, want %v", err, ErrNegativeShift)
+	}
+}
@@ -0,0 +1,250 @@
+// Package lox defines Value, the representation of Lox values shared by the parser, the optimizer and the
+// interpreter.
+package lox
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Kind says which kind of value a Value holds.
+type Kind uint8
+
+const (
+	NilKind Kind = iota
+	BoolKind
+	IntKind
+	FloatKind
+	StringKind
+	ObjectKind
+)
+
+// Value is a Lox value. Booleans, integers and floats are stored in the bits payload, so making one doesn't
+// allocate: a boolean as 0 or 1, an integer as its two's complement bits and a float as its IEEE 754 bits. Strings
+// and objects such as functions and lists are stored in the object payload.
+//
+// The zero Value is nil.
+type Value struct {
+	kind   Kind
+	bits   uint64
+	object interface{}
+}
+
+// Nil is the Lox nil value.
+var Nil = Value{}
+
+func Bool(b bool) Value {
+	if b {
+		return Value{kind: BoolKind, bits: 1}
+	}
+
+	return Value{kind: BoolKind}
+}
+
+func Int(num int64) Value {
+	return Value{kind: IntKind, bits: uint64(num)}
+}
+
+func Float(num float64) Value {
+	return Value{kind: FloatKind, bits: math.Float64bits(num)}
+}
+
+func String(str string) Value {
+	return Value{kind: StringKind, object: str}
+}
+
+// Object returns a Value holding a value implemented by the interpreter, like a function or a list. Objects are
+// compared by identity, so obj should be a pointer.
+func Object(obj interface{}) Value {
+	return Value{kind: ObjectKind, object: obj}
+}
+
+// FromLiteral converts the literal value of a token (nil, a bool, an int64, a float64 or a string) to a Value.
+func FromLiteral(literal interface{}) Value {
+	switch l := literal.(type) {
+	case bool:
+		return Bool(l)
+	case int64:
+		return Int(l)
+	case float64:
+		return Float(l)
+	case string:
+		return String(l)
+	}
+
+	return Nil
+}
+
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+func (v Value) IsNil() bool {
+	return v.kind == NilKind
+}
+
+func (v Value) IsBool() bool {
+	return v.kind == BoolKind
+}
+
+// IsNumber reports whether v is an integer or a float.
+func (v Value) IsNumber() bool {
+	return v.kind == IntKind || v.kind == FloatKind
+}
+
+func (v Value) IsInt() bool {
+	return v.kind == IntKind
+}
+
+func (v Value) IsFloat() bool {
+	return v.kind == FloatKind
+}
+
+func (v Value) IsString() bool {
+	return v.kind == StringKind
+}
+
+// AsBool returns the boolean held by v, which must be a boolean.
+func (v Value) AsBool() bool {
+	return v.bits != 0
+}
+
+// AsNumber returns the number held by v, which must be an integer or a float, converted to a float.
+func (v Value) AsNumber() float64 {
+	if v.kind == IntKind {
+		return float64(v.AsInt())
+	}
+
+	return math.Float64frombits(v.bits)
+}
+
+// AsInt returns the integer held by v, which must be an integer.
+func (v Value) AsInt() int64 {
+	return int64(v.bits)
+}
+
+// ToInt returns the integer that v is equal to. It returns false if v isn't an integer or a float with no
+// fractional part that fits in an int64.
+func (v Value) ToInt() (int64, bool) {
+	switch v.kind {
+	case IntKind:
+		return v.AsInt(), true
+	case FloatKind:
+		if num := v.AsNumber(); num == math.Trunc(num) && num >= math.MinInt64 && num < math.MaxInt64 {
+			return int64(num), true
+		}
+	}
+
+	return 0, false
+}
+
+// AsString returns the string held by v, or "" if v isn't a string.
+func (v Value) AsString() string {
+	str, _ := v.object.(string)
+	return str
+}
+
+// AsObject returns the object held by v, or nil if v isn't an object.
+func (v Value) AsObject() interface{} {
+	if v.kind != ObjectKind {
+		return nil
+	}
+
+	return v.object
+}
+
+// Truthy reports whether v counts as true in a condition: everything except nil and false does.
+func (v Value) Truthy() bool {
+	switch v.kind {
+	case NilKind:
+		return false
+	case BoolKind:
+		return v.AsBool()
+	}
+
+	return true
+}
+
+// Equal reports whether a and b are equal in the sense of Lox's == operator. An integer is equal to a float with the
+// same value. Otherwise values of different kinds are never equal, and objects are equal only if they are the same
+// object.
+func Equal(a Value, b Value) bool {
+	if a.IsNumber() && b.IsNumber() {
+		return numbersEqual(a, b)
+	}
+
+	if a.kind != b.kind {
+		return false
+	}
+
+	switch a.kind {
+	case NilKind:
+		return true
+	case BoolKind:
+		return a.bits == b.bits
+	}
+
+	return a.object == b.object
+}
+
+// Interface returns v as a plain Go value: nil, a bool, an int64, a float64, a string or the object.
+func (v Value) Interface() interface{} {
+	switch v.kind {
+	case BoolKind:
+		return v.AsBool()
+	case IntKind:
+		return v.AsInt()
+	case FloatKind:
+		return v.AsNumber()
+	}
+
+	return v.object
+}
+
+// String returns v the way the print statement shows it. Objects are formatted with fmt, so they can control
+// how they look by implementing fmt.Stringer.
+func (v Value) String() string {
+	switch v.kind {
+	case NilKind:
+		return "nil"
+	case BoolKind:
+		return fmt.Sprint(v.AsBool())
+	case IntKind:
+		return strconv.FormatInt(v.AsInt(), 10)
+	case FloatKind:
+		return formatFloat(v.AsNumber())
+	case StringKind:
+		return v.AsString()
+	}
+
+	return fmt.Sprint(v.object)
+}
+
+// formatFloat formats num the way jlox does, which is Java's Double.toString with any ".0" suffix removed. Numbers
+// from 10^-3 up to 10^7 are written out in full and the rest use scientific notation, like 1.5E-5.
+func formatFloat(num float64) string {
+	switch {
+	case math.IsNaN(num):
+		return "NaN"
+	case math.IsInf(num, 1):
+		return "Infinity"
+	case math.IsInf(num, -1):
+		return "-Infinity"
+	}
+
+	if abs := math.Abs(num); abs == 0 || (abs >= 1e-3 && abs < 1e7) {
+		return strconv.FormatFloat(num, 'f', -1, 64)
+	}
+
+	str := strconv.FormatFloat(num, 'E', -1, 64)
+	idx := strings.IndexByte(str, 'E')
+	mantissa, exponent := str[:idx], str[idx+1:]
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	exp, _ := strconv.Atoi(exponent)
+
+	return mantissa + "E" + strconv.Itoa(exp)
+}
@@ -0,0 +1,129 @@
+package lox
+
+import (
+	"math"
+	"testing"
+	"unsafe"
+)
+
+type object struct {
+	name string
+}
+
+func (o *object) String() string {
+	return "<" + o.name + ">"
+}
+
+func TestEqual(t *testing.T) {
+	obj := &object{name: "a"}
+
+	tests := []struct {
+		a        Value
+		b        Value
+		expected bool
+	}{
+		{Nil, Nil, true},
+		{Nil, Bool(false), false},
+		{Bool(true), Bool(true), true},
+		{Bool(true), Float(1), false},
+		{Float(0), Bool(false), false},
+		{Float(2.5), Float(2.5), true},
+		{String("a"), String("a"), true},
+		{String("a"), String("b"), false},
+		{Object(obj), Object(obj), true},
+		{Object(obj), Object(&object{name: "a"}), false},
+	}
+
+	for _, test := range tests {
+		if actual := Equal(test.a, test.b); actual != test.expected {
+			t.Errorf("Equal(%v, %v) = %v, want %v", test.a, test.b, actual, test.expected)
+		}
+	}
+}
+
+func TestTruthy(t *testing.T) {
+	tests := []struct {
+		value    Value
+		expected bool
+	}{
+		{Nil, false},
+		{Bool(false), false},
+		{Bool(true), true},
+		{Float(0), true},
+		{String(""), true},
+		{Object(&object{}), true},
+	}
+
+	for _, test := range tests {
+		if actual := test.value.Truthy(); actual != test.expected {
+			t.Errorf("%v.Truthy() = %v, want %v", test.value, actual, test.expected)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		value    Value
+		expected string
+	}{
+		{Nil, "nil"},
+		{Bool(true), "true"},
+		{Float(3), "3"},
+		{Float(2.5), "2.5"},
+		{String("hi"), "hi"},
+		{Object(&object{name: "fn"}), "<fn>"},
+	}
+
+	for _, test := range tests {
+		if actual := test.value.String(); actual != test.expected {
+			t.Errorf("String() = %v, want %v", actual, test.expected)
+		}
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	tests := map[float64]string{
+		0:         "0",
+		-1.5:      "-1.5",
+		0.001:     "0.001",
+		0.0001:    "1.0E-4",
+		1234567:   "1234567",
+		1e7:       "1.0E7",
+		1.25e-10:  "1.25E-10",
+		123456789: "1.23456789E8",
+	}
+
+	for num, expected := range tests {
+		if actual := Float(num).String(); actual != expected {
+			t.Errorf("Float(%v).String() = %v, want %v", num, actual, expected)
+		}
+	}
+}
+
+func TestIntegerEquality(t *testing.T) {
+	if !Equal(Int(1), Float(1)) {
+		t.Error("Expected 1 to equal 1.0.")
+	}
+
+	// 2^53 + 1 can't be represented as a float, so it mustn't equal the float it rounds to.
+	if Equal(Int(9007199254740993), Float(9007199254740992)) {
+		t.Error("Expected 9007199254740993 to not equal 9007199254740992.0.")
+	}
+}
+
+func TestNumbersShareOnePayload(t *testing.T) {
+	// on 64-bit platforms: the kind, one 8-byte numeric payload and the object interface
+	if size := unsafe.Sizeof(Value{}); unsafe.Sizeof(uintptr(0)) == 8 && size != 32 {
+		t.Errorf("Expected a Value to take 32 bytes, but it took %v.", size)
+	}
+
+	if n := Int(math.MinInt64).AsInt(); n != math.MinInt64 {
+		t.Errorf("Expected the minimum integer to round trip, but got %v.", n)
+	}
+	if f := Float(-0.5).AsNumber(); f != -0.5 {
+		t.Errorf("Expected -0.5 to round trip, but got %v.", f)
+	}
+	if !Float(math.NaN()).IsFloat() || Equal(Float(math.NaN()), Float(math.NaN())) {
+		t.Error("Expected NaN to be a float that isn't equal to itself.")
+	}
+}
@@ -0,0 +1,67 @@
+package loxtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const conformanceDir = "testdata/craftinginterpreters"
+
+// skipped lists the parts of the Crafting Interpreters suite that golox doesn't pass, keyed by directory or by
+// file, relative to conformanceDir.
+var skipped = map[string]string{
+	// golox extensions make these programs valid or change what they print.
+	"for/fun_in_body.lox":           "golox has anonymous functions",
+	"if/fun_in_else.lox":            "golox has anonymous functions",
+	"if/fun_in_then.lox":            "golox has anonymous functions",
+	"while/fun_in_body.lox":         "golox has anonymous functions",
+	"for/statement_condition.lox":   "golox has map literals",
+	"for/statement_increment.lox":   "golox has map literals",
+	"for/statement_initializer.lox": "golox has map literals",
+	"number/leading_dot.lox":        "golox reports a leading dot in the scanner",
+	"number/literals.lox":           "golox has integers, so -0 is 0",
+	"operator/negate.lox":           "golox has the -- operator",
+	"unexpected_character.lox":      "golox has the | operator",
+}
+
+func TestConformance(t *testing.T) {
+	for path := range skipped {
+		if _, err := os.Stat(filepath.Join(conformanceDir, filepath.FromSlash(path))); err != nil {
+			t.Errorf("Skipped path %v isn't in the suite: %v", path, err)
+		}
+	}
+
+	err := filepath.Walk(conformanceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(conformanceDir, path)
+		if err != nil {
+			return err
+		}
+
+		if reason, ok := skipped[filepath.ToSlash(rel)]; ok {
+			t.Logf("Skipping %v: %v", rel, reason)
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() || filepath.Ext(path) != ".lox" {
+			return nil
+		}
+
+		result := RunFile(path)
+		for _, failure := range result.Failures {
+			t.Errorf("%v: %v", rel, failure)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
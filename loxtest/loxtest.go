@@ -0,0 +1,235 @@
+// Package loxtest runs Lox scripts and checks what they print against expectation comments written in the
+// scripts themselves. It uses the format of the Crafting Interpreters test suite:
+//
+//	print 1 + 2; // expect: 3
+//	print -"a"; // expect runtime error: Operand must be a number.
+//	print end; // Error at 'end': Expect expression.
+//	// [line 7] Error at end: Expect '}' after block.
+//
+// An error comment without a line number refers to the line it is on.
+package loxtest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maleksiuk/golox/errorreport"
+	"github.com/maleksiuk/golox/interpreter"
+	"github.com/maleksiuk/golox/parser"
+	"github.com/maleksiuk/golox/scanner"
+)
+
+// The patterns are only matched against the comment at the end of a line, not against code such as a string
+// literal containing "// Error". Like upstream, they can start anywhere in the comment: one test expects an error
+// with "// // expect runtime error:".
+var (
+	expectOutputPattern       = regexp.MustCompile(`// expect: ?(.*)`)
+	expectRuntimeErrorPattern = regexp.MustCompile(`// expect runtime error: (.+)`)
+	expectErrorPattern        = regexp.MustCompile(`// (Error.*)`)
+	// The upstream suite also has "[c line N]" errors, which only apply to its C interpreter.
+	expectErrorLinePattern = regexp.MustCompile(`// \[((java|c) )?line (\d+)\] (Error.*)`)
+)
+
+// Output is a line a script is expected to print, along with the line of the script that expects it.
+type Output struct {
+	Line int
+	Text string
+}
+
+// Expectations are what a script's comments say running it should produce. Errors are formatted the way the
+// error report prints them, e.g. "[line 3] Error at 'end': Expect expression.".
+type Expectations struct {
+	Output []Output
+	Errors []string
+}
+
+// ParseExpectations reads the expectation comments in a script.
+func ParseExpectations(source string) Expectations {
+	var expected Expectations
+	inString := false
+
+	for idx, text := range strings.Split(source, "\n") {
+		line := idx + 1
+
+		start := commentStart(text, &inString)
+		if start < 0 {
+			continue
+		}
+		text = text[start:]
+
+		if match := expectOutputPattern.FindStringSubmatch(text); match != nil {
+			expected.Output = append(expected.Output, Output{Line: line, Text: match[1]})
+			continue
+		}
+
+		if match := expectRuntimeErrorPattern.FindStringSubmatch(text); match != nil {
+			expected.Errors = append(expected.Errors, fmt.Sprintf("[line %d] Runtime error: %v", line, match[1]))
+			continue
+		}
+
+		if match := expectErrorLinePattern.FindStringSubmatch(text); match != nil {
+			if match[2] != "c" {
+				errorLine, _ := strconv.Atoi(match[3])
+				expected.Errors = append(expected.Errors, fmt.Sprintf("[line %d] %v", errorLine, match[4]))
+			}
+			continue
+		}
+
+		if match := expectErrorPattern.FindStringSubmatch(text); match != nil {
+			expected.Errors = append(expected.Errors, fmt.Sprintf("[line %d] %v", line, match[1]))
+		}
+	}
+
+	return expected
+}
+
+// commentStart returns the index of the '//' that starts a comment in line, or -1 if there is none. A '//' in a
+// string literal doesn't count. inString says whether line starts inside a string and is updated for the next
+// line, because strings can span lines.
+func commentStart(line string, inString *bool) int {
+	for idx := 0; idx < len(line); idx++ {
+		switch {
+		case line[idx] == '"':
+			*inString = !*inString
+		case !*inString && strings.HasPrefix(line[idx:], "//"):
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// Result is the outcome of running one script. The script passed if there are no failures.
+type Result struct {
+	Path     string
+	Failures []string
+}
+
+func (result Result) Passed() bool {
+	return len(result.Failures) == 0
+}
+
+func (result *Result) fail(format string, a ...interface{}) {
+	result.Failures = append(result.Failures, fmt.Sprintf(format, a...))
+}
+
+// errorCollector is an errorreport.Printer that keeps the reported errors instead of printing them.
+type errorCollector struct {
+	errors []string
+}
+
+func (collector *errorCollector) Printf(format string, a ...interface{}) (n int, err error) {
+	str := fmt.Sprintf(format, a...)
+	collector.errors = append(collector.errors, strings.TrimSuffix(str, "\n"))
+	return len(str), nil
+}
+
+// RunFile runs the script at path and checks it against its expectation comments.
+func RunFile(path string) Result {
+	result := Result{Path: path}
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		result.fail("Could not read script: %v", err)
+		return result
+	}
+	source := string(buf)
+
+	var stdout bytes.Buffer
+	i := interpreter.NewInterpreter(interpreter.WithStdout(&stdout), interpreter.WithStdin(strings.NewReader("")))
+	if err := i.SetScriptPath(path); err != nil {
+		result.fail("Could not set script path: %v", err)
+		return result
+	}
+
+	collector := &errorCollector{}
+	errorReport := errorreport.ErrorReport{Printer: collector}
+
+	statements := parser.ParseStream(scanner.New(strings.NewReader(source), &errorReport), &errorReport)
+	if !errorReport.HadError {
+		i.Interpret(statements, &errorReport)
+	}
+
+	expected := ParseExpectations(source)
+	result.checkOutput(expected.Output, stdout.String())
+	result.checkErrors(expected.Errors, collector.errors)
+
+	return result
+}
+
+func (result *Result) checkOutput(expected []Output, stdout string) {
+	actual := strings.Split(stdout, "\n")
+	// Every print ends with a newline, so the last element is empty.
+	actual = actual[:len(actual)-1]
+
+	for idx, text := range actual {
+		if idx >= len(expected) {
+			result.fail("Got output '%v' when none was expected.", text)
+			continue
+		}
+
+		if text != expected[idx].Text {
+			result.fail("Expected output '%v' on line %d and got '%v'.", expected[idx].Text, expected[idx].Line, text)
+		}
+	}
+
+	for idx := len(actual); idx < len(expected); idx++ {
+		result.fail("Missing expected output '%v' on line %d.", expected[idx].Text, expected[idx].Line)
+	}
+}
+
+// checkErrors compares the errors regardless of order, since a scanner error on a later line is reported
+// before a parser error on an earlier one.
+func (result *Result) checkErrors(expected []string, actual []string) {
+	remaining := make(map[string]int)
+	for _, err := range expected {
+		remaining[err]++
+	}
+
+	for _, err := range actual {
+		if remaining[err] > 0 {
+			remaining[err]--
+			continue
+		}
+		result.fail("Unexpected error: %v", err)
+	}
+
+	for _, err := range expected {
+		if remaining[err] > 0 {
+			remaining[err]--
+			result.fail("Missing expected error: %v", err)
+		}
+	}
+}
+
+// RunDir runs every .lox file in dir and its subdirectories, in lexical order.
+func RunDir(dir string) ([]Result, error) {
+	paths := make([]string, 0, 50)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(path) == ".lox" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	results := make([]Result, len(paths))
+	for idx, path := range paths {
+		results[idx] = RunFile(path)
+	}
+
+	return results, nil
+}
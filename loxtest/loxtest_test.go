@@ -0,0 +1,76 @@
+package loxtest
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseExpectations(t *testing.T) {
+	source := `print 1; // expect: 1
+print ""; // expect:
+a(); // expect runtime error: Undefined variable 'a'.
+var = 1; // Error at '=': Expect variable name.
+// [line 7] Error at end: Expect '}' after block.
+// [java line 8] Error: Unexpected character.
+// [c line 9] Error: Unexpected character.
+var s = "// Error: not an expectation";
+print "a
+// expect: b"; // expect: a`
+
+	expected := Expectations{
+		Output: []Output{{Line: 1, Text: "1"}, {Line: 2, Text: ""}, {Line: 10, Text: "a"}},
+		Errors: []string{
+			"[line 3] Runtime error: Undefined variable 'a'.",
+			"[line 4] Error at '=': Expect variable name.",
+			"[line 7] Error at end: Expect '}' after block.",
+			"[line 8] Error: Unexpected character.",
+		},
+	}
+
+	if actual := ParseExpectations(source); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v.", expected, actual)
+	}
+}
+
+func TestRunDirPassing(t *testing.T) {
+	results, err := RunDir(filepath.Join("testdata", "passing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 {
+		t.Errorf("Expected 3 results but got %v.", len(results))
+	}
+	for _, result := range results {
+		if !result.Passed() {
+			t.Errorf("Expected %v to pass, but it failed with %v.", result.Path, result.Failures)
+		}
+	}
+}
+
+func TestRunFileFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []string
+	}{
+		{"wrong.lox", []string{
+			"Expected output '2' on line 1 and got '1'.",
+			"Expected output '4' on line 3 and got '3'.",
+		}},
+		{"missing.lox", []string{
+			"Missing expected output '1' on line 1.",
+			"Missing expected output '2' on line 2.",
+			"Unexpected error: [line 4] Error at '=': Expect variable name.",
+			"Missing expected error: [line 3] Runtime error: Undefined variable 'x'.",
+			"Missing expected error: [line 4] Error at 'var': Expect variable name.",
+		}},
+	}
+
+	for _, test := range tests {
+		result := RunFile(filepath.Join("testdata", "failing", test.name))
+		if !reflect.DeepEqual(result.Failures, test.failures) {
+			t.Errorf("Expected %v to fail with %q, but got %q.", test.name, test.failures, result.Failures)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Package optimizer rewrites a parsed program into an equivalent one that does less work when interpreted. It
+// folds operations on literals into a single literal, simplifies double negation and removes branches and loops
+// whose conditions are constant.
+//
+// Only operations that can't fail are folded. Anything that would be a runtime error, like "a" - 1, is left for
+// the interpreter, which reports it on the line of the original operator token.
+package optimizer
+
+import (
+	"github.com/maleksiuk/golox/ast"
+	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/stmt"
+	"github.com/maleksiuk/golox/toks"
+)
+
+// Optimize rewrites the statements in place and returns the optimized list.
+func Optimize(statements []stmt.Stmt) []stmt.Stmt {
+	optimized := make([]stmt.Stmt, 0, len(statements))
+	for _, statement := range statements {
+		if s := ast.Rewrite(statement, rewrite); s != nil {
+			optimized = append(optimized, s.(stmt.Stmt))
+		}
+	}
+
+	return optimized
+}
+
+// rewrite is the ast.Rewrite callback, so the children of node have already been optimized. It returns nil for a
+// statement that does nothing and can be removed.
+func rewrite(node ast.Node) ast.Node {
+	switch n := node.(type) {
+	case *expr.Binary:
+		return optimizeBinary(n)
+	case *expr.Logical:
+		return optimizeLogical(n)
+	case *expr.Conditional:
+		return optimizeConditional(n)
+	case *expr.Grouping:
+		if literal, ok := n.Expression.(*expr.Literal); ok {
+			return folded(n, literal.Value)
+		}
+	case *expr.Unary:
+		return optimizeUnary(n)
+
+	case *stmt.Conditional:
+		n.Condition = condition(n.Condition)
+		if literal, ok := n.Condition.(*expr.Literal); ok {
+			if literal.Value.Truthy() {
+				return n.ThenStatement
+			}
+			return n.ElseStatement
+		}
+
+		// a removed else branch just leaves the if statement without one
+		n.ThenStatement = branch(n.ThenStatement)
+	case *stmt.While:
+		n.Condition = condition(n.Condition)
+		if literal, ok := n.Condition.(*expr.Literal); ok && !literal.Value.Truthy() {
+			return nil
+		}
+
+		n.Body = branch(n.Body)
+	}
+
+	return node
+}
+
+// branch returns the body of an if statement or loop, which can't be removed outright since its parent needs a
+// statement. An empty block stands in for a removed one.
+func branch(statement stmt.Stmt) stmt.Stmt {
+	if statement == nil {
+		return &stmt.Block{}
+	}
+
+	return statement
+}
+
+// condition simplifies an expression whose value is only checked for truthiness, so !!x can become x.
+func condition(expression expr.Expr) expr.Expr {
+	for {
+		outer, ok := expression.(*expr.Unary)
+		if !ok || outer.Operator.TokenType != toks.Bang {
+			return expression
+		}
+		inner, ok := outer.Right.(*expr.Unary)
+		if !ok || inner.Operator.TokenType != toks.Bang {
+			return expression
+		}
+
+		expression = inner.Right
+	}
+}
+
+// folded returns the literal that expression folds to, which covers the same source code as expression.
+func folded(expression expr.Expr, value lox.Value) *expr.Literal {
+	return &expr.Literal{Value: value, ValuePos: expression.Pos(), ValueEnd: expression.End()}
+}
+
+func optimizeBinary(binary *expr.Binary) expr.Expr {
+	left, leftOk := binary.Left.(*expr.Literal)
+	right, rightOk := binary.Right.(*expr.Literal)
+	if !leftOk || !rightOk {
+		return binary
+	}
+
+	if value, ok := foldBinary(binary.Operator.TokenType, left.Value, right.Value); ok {
+		return folded(binary, value)
+	}
+
+	return binary
+}
+
+// foldBinary computes the result of a binary operation the way the interpreter would. It returns false if the
+// operation would be a runtime error.
+func foldBinary(operator toks.TokenType, left lox.Value, right lox.Value) (lox.Value, bool) {
+	switch operator {
+	case toks.EqualEqual:
+		return lox.Bool(lox.Equal(left, right)), true
+	case toks.BangEqual:
+		return lox.Bool(!lox.Equal(left, right)), true
+	case toks.Plus:
+		if left.IsString() && right.IsString() {
+			return lox.String(left.AsString() + right.AsString()), true
+		}
+	case toks.Ampersand, toks.Pipe, toks.Caret, toks.LessLess, toks.GreaterGreater:
+		return foldBitwise(operator, left, right)
+	}
+
+	if !left.IsNumber() || !right.IsNumber() {
+		return lox.Nil, false
+	}
+
+	var result lox.Value
+	var err error
+	switch operator {
+	case toks.Plus:
+		result, err = lox.Add(left, right)
+	case toks.Minus:
+		result, err = lox.Subtract(left, right)
+	case toks.Star:
+		result, err = lox.Multiply(left, right)
+	case toks.Slash:
+		result = lox.Divide(left, right)
+	case toks.TildeSlash:
+		result, err = lox.FloorDivide(left, right)
+	case toks.Percent:
+		result, err = lox.Modulo(left, right)
+	case toks.StarStar:
+		result, err = lox.Power(left, right)
+	case toks.Greater:
+		result = lox.Bool(lox.Less(right, left))
+	case toks.GreaterEqual:
+		result = lox.Bool(lox.LessEqual(right, left))
+	case toks.Less:
+		result = lox.Bool(lox.Less(left, right))
+	case toks.LessEqual:
+		result = lox.Bool(lox.LessEqual(left, right))
+	default:
+		return lox.Nil, false
+	}
+
+	return result, err == nil
+}
+
+// foldBitwise computes the result of an operator that requires integer operands.
+func foldBitwise(operator toks.TokenType, left lox.Value, right lox.Value) (lox.Value, bool) {
+	_, leftOk := left.ToInt()
+	_, rightOk := right.ToInt()
+	if !leftOk || !rightOk {
+		return lox.Nil, false
+	}
+
+	var result lox.Value
+	var err error
+	switch operator {
+	case toks.Ampersand:
+		result = lox.BitwiseAnd(left, right)
+	case toks.Pipe:
+		result = lox.BitwiseOr(left, right)
+	case toks.Caret:
+		result = lox.BitwiseXor(left, right)
+	case toks.LessLess:
+		result, err = lox.ShiftLeft(left, right)
+	case toks.GreaterGreater:
+		result, err = lox.ShiftRight(left, right)
+	}
+
+	return result, err == nil
+}
+
+func optimizeLogical(logical *expr.Logical) expr.Expr {
+	left, ok := logical.Left.(*expr.Literal)
+	if !ok {
+		return logical
+	}
+
+	// "and" and "or" produce one of their operands, so a literal left operand decides which.
+	if left.Value.Truthy() == (logical.Operator.TokenType == toks.Or) {
+		return left
+	}
+
+	return logical.Right
+}
+
+func optimizeConditional(conditional *expr.Conditional) expr.Expr {
+	conditional.Condition = condition(conditional.Condition)
+
+	if literal, ok := conditional.Condition.(*expr.Literal); ok {
+		if literal.Value.Truthy() {
+			return conditional.Then
+		}
+		return conditional.Else
+	}
+
+	return conditional
+}
+
+func optimizeUnary(unary *expr.Unary) expr.Expr {
+	if literal, ok := unary.Right.(*expr.Literal); ok {
+		if unary.Operator.TokenType == toks.Bang {
+			return folded(unary, lox.Bool(!literal.Value.Truthy()))
+		}
+		if literal.Value.IsNumber() && unary.Operator.TokenType == toks.Minus {
+			if value, err := lox.Negate(literal.Value); err == nil {
+				return folded(unary, value)
+			}
+		}
+		if _, ok := literal.Value.ToInt(); ok && unary.Operator.TokenType == toks.Tilde {
+			return folded(unary, lox.BitwiseNot(literal.Value))
+		}
+		return unary
+	}
+
+	// !!x is x when x is already a boolean.
+	if inner, ok := unary.Right.(*expr.Unary); ok && unary.Operator.TokenType == toks.Bang &&
+		inner.Operator.TokenType == toks.Bang && isBoolean(inner.Right) {
+		return inner.Right
+	}
+
+	return unary
+}
+
+// isBoolean reports whether the expression always produces true or false.
+func isBoolean(expression expr.Expr) bool {
+	switch e := expression.(type) {
+	case *expr.Unary:
+		return e.Operator.TokenType == toks.Bang
+	case *expr.Binary:
+		switch e.Operator.TokenType {
+		case toks.EqualEqual, toks.BangEqual, toks.Greater, toks.GreaterEqual, toks.Less, toks.LessEqual:
+			return true
+		}
+	case *expr.Grouping:
+		return isBoolean(e.Expression)
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/maleksiuk/golox/errorreport"
+	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/parser"
+	"github.com/maleksiuk/golox/scanner"
+	"github.com/maleksiuk/golox/stmt"
+	"github.com/maleksiuk/golox/toks"
+	"github.com/maleksiuk/golox/tools"
+)
+
+func optimize(t *testing.T, code string) []stmt.Stmt {
+	errorReport := errorreport.ErrorReport{Printer: errorreport.NewMockPrinter()}
+	tokens := scanner.ScanTokens(code, &errorReport)
+	statements := parser.Parse(tokens, &errorReport)
+	if errorReport.HadError {
+		t.Fatalf("Could not parse %v", code)
+	}
+
+	return Optimize(statements)
+}
+
+func TestFoldExpressions(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{`60 * 60 * 24;`, "86400"},
+		{`"a" + "b" + "c";`, "abc"},
+		{`(1 + 2) * -3;`, "-9"},
+		{`10 % 4 + 2 ** 3;`, "10"},
+		{`1 < 2 == true;`, "true"},
+		{`nil != false;`, "true"},
+		{`!nil;`, "true"},
+		{`true and x;`, "x"},
+		{`nil and x;`, "nil"},
+		{`0 or x;`, "0"},
+		{`false ? x : y;`, "y"},
+		{`!!(a < b);`, "(group (< a b))"},
+		{`!!!x;`, "(! x)"},
+		{`!!x;`, "(! (! x))"},
+		{`x + 2 * 3;`, "(+ x 6)"},
+		{`f(1 + 1)[2 - 2];`, "([] (call f 2) 0)"},
+		{`"a" - 1;`, "(- a 1)"},
+		{`1 + nil;`, "(+ 1 nil)"},
+		{`7 ~/ 2 + 1 / 2;`, "3.5"},
+		{`9223372036854775807 + 1;`, "(+ 9223372036854775807 1)"},
+		{`1 ~/ 0;`, "(~/ 1 0)"},
+		{`0xF0 | 0x0F & ~1 << 1;`, "252"},
+		{`1 << -1;`, "(<< 1 -1)"},
+		{`1.5 & 1;`, "(& 1.5 1)"},
+	}
+
+	for _, test := range tests {
+		statements := optimize(t, test.code)
+		expression := statements[0].(*stmt.Expression).Expression
+		if actual := tools.PrintAst(expression); actual != test.expected {
+			t.Errorf("Expected %v to be optimized to %v, but got %v.", test.code, test.expected, actual)
+		}
+	}
+}
+
+func TestUnfoldedOperatorKeepsToken(t *testing.T) {
+	statements := optimize(t, "print 1 +\n  (2 - \"a\");")
+
+	binary := statements[0].(*stmt.Print).Expression.(*expr.Binary)
+	inner := binary.Right.(*expr.Grouping).Expression.(*expr.Binary)
+	if inner.Operator.TokenType != toks.Minus || inner.Operator.Line != 2 {
+		t.Errorf("Expected the '-' operator on line 2 to be kept, but got %v.", inner.Operator)
+	}
+}
+
+func TestConstantConditions(t *testing.T) {
+	statements := optimize(t, `
+	  if (true) print "then"; else print "else";
+	  if (!!false) print "then"; else print "else";
+	  if (nil) print "then";
+	  while (false) print "body";
+	  for (var i = 0; false; i = i + 1) print i;
+	  if (x) while (1 > 2) print "body";
+	  while (!!x) print "body";
+	`)
+
+	if len(statements) != 5 {
+		t.Fatalf("Expected 5 statements but got %v.", len(statements))
+	}
+
+	for idx, expected := range []string{"then", "else"} {
+		value := statements[idx].(*stmt.Print).Expression.(*expr.Literal).Value
+		if value != lox.String(expected) {
+			t.Errorf("Expected statement %v to print %v, but it prints %v.", idx, expected, value)
+		}
+	}
+
+	loop := statements[2].(*stmt.Block)
+	if len(loop.Statements) != 1 {
+		t.Errorf("Expected only the loop variable to be left, but got %v statements.", len(loop.Statements))
+	}
+
+	conditional := statements[3].(*stmt.Conditional)
+	if body, ok := conditional.ThenStatement.(*stmt.Block); !ok || len(body.Statements) != 0 {
+		t.Errorf("Expected the removed loop to be replaced with an empty block, but got %v.", conditional.ThenStatement)
+	}
+
+	while := statements[4].(*stmt.While)
+	if _, ok := while.Condition.(*expr.Variable); !ok {
+		t.Errorf("Expected the loop condition to be simplified to x, but got %v.", tools.PrintAst(while.Condition))
+	}
+}
+
+func TestOptimizeFunctionBodies(t *testing.T) {
+	statements := optimize(t, `
+	  fun f() { return 2 * 3; }
+	  var g = fun () { return "a" + "b"; };
+	`)
+
+	ret := statements[0].(*stmt.Function).Body[0].(*stmt.Return)
+	if value := ret.Value.(*expr.Literal).Value; value != lox.Int(6) {
+		t.Errorf("Expected the function to return 6, but got %v.", value)
+	}
+
+	lambda := statements[1].(*stmt.Var).Initializer.(*expr.Function)
+	ret = lambda.Body[0].(*stmt.Return)
+	if value := ret.Value.(*expr.Literal).Value; value != lox.String("ab") {
+		t.Errorf("Expected the lambda to return ab, but got %v.", value)
+	}
+}
+
+func TestFoldedLiteralCoversExpression(t *testing.T) {
+	statements := optimize(t, "print\n  (1 + 2) * -3;")
+	literal := statements[0].(*stmt.Print).Expression.(*expr.Literal)
+
+	start, end := toks.Position{Line: 2, Column: 3}, toks.Position{Line: 2, Column: 15}
+	if literal.Pos() != start || literal.End() != end {
+		t.Errorf("Expected the folded literal to cover %v-%v, but it covered %v-%v", start, end, literal.Pos(), literal.End())
+	}
+}
@@ -2,26 +2,43 @@
 Package parser is used to convert a list of tokens to an abstract syntax tree using the following rules:
 
 expression     → assignment ;
-assignment     → IDENTIFIER "=" assignment
-			   | logic_or ;
+assignment     → ( IDENTIFIER | call "[" expression "]" ) ( "=" | "+=" | "-=" | "*=" | "/=" | "%=" ) assignment
+			   | conditional ;
+conditional    → logic_or ( "?" expression ":" conditional )? ;
 logic_or       → logic_and ( "or" logic_and )* ;
 logic_and      → equality ( "and" equality )* ;
-equality       → comparison ( ( "!=" | "==" ) comparison )* ;
-comparison     → addition ( ( ">" | ">=" | "<" | "<=" ) addition )* ;
+equality       → bit_or ( ( "!=" | "==" ) bit_or )* ;
+bit_or         → bit_xor ( "|" bit_xor )* ;
+bit_xor        → bit_and ( "^" bit_and )* ;
+bit_and        → comparison ( "&" comparison )* ;
+comparison     → shift ( ( ">" | ">=" | "<" | "<=" ) shift )* ;
+shift          → addition ( ( "<<" | ">>" ) addition )* ;
 addition       → multiplication ( ( "-" | "+" ) multiplication )* ;
-multiplication → unary ( ( "/" | "*" ) unary )* ;
-unary          → ( "!" | "-" ) unary
-			   | call ;
-call           → primary ( "(" arguments? ")" )* ;
+multiplication → unary ( ( "/" | "~/" | "*" | "%" ) unary )* ;
+unary          → ( "!" | "-" | "~" ) unary
+			   | ( "++" | "--" ) unary
+			   | exponent ;
+exponent       → postfix ( "**" unary )? ;
+postfix        → call ( "++" | "--" )? ;
+call           → primary ( "(" arguments? ")" | "." IDENTIFIER | "[" expression "]" )* ;
 arguments      → expression ( "," expression )* ;
 primary        → NUMBER | STRING | "false" | "true" | "nil"
 			   | "(" expression ")"
-			   | IDENTIFIER ;
+			   | IDENTIFIER
+			   | "[" arguments? "]"
+			   | "{" ( entry ( "," entry )* )? "}"
+			   | lambda ;
+entry          → expression ":" expression ;
+lambda         → "fun" "(" parameters? ")" block
+			   | "(" parameters? ")" "=>" expression ;
 
 program     → declaration* EOF ;
-declaration → funDecl
+declaration → importDecl
+            | funDecl
             | varDecl
 			| statement ;
+importDecl  → "import" STRING "as" IDENTIFIER ";"
+            | "from" STRING "import" IDENTIFIER ( "," IDENTIFIER )* ";" ;
 varDecl     → "var" IDENTIFIER ( "=" expression )? ";" ;
 funDecl        → "fun" function ;
 function       → IDENTIFIER "(" parameters? ")" block ;
@@ -31,11 +48,21 @@ statement → exprStmt
           | printStmt
 		  | whileStmt
 		  | forStmt
+		  | breakStmt
+		  | continueStmt
+		  | throwStmt
+		  | tryStmt
+		  | returnStmt
           | block ;
 
 exprStmt  → expression ";" ;
 printStmt → "print" expression ";" ;
 whileStmt → "while" "(" expression ")" statement ;
+breakStmt → "break" ";" ;
+continueStmt → "continue" ";" ;
+throwStmt → "throw" expression ";" ;
+returnStmt → "return" expression? ";" ;
+tryStmt   → "try" block ( "catch" "(" IDENTIFIER ")" block )? ( "finally" block )? ;
 forStmt   → "for" "(" ( varDecl | exprStmt | ";" )
                       expression? ";"
                       expression? ")" statement ;
@@ -50,14 +77,51 @@ import (
 
 	"github.com/maleksiuk/golox/errorreport"
 	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
 	"github.com/maleksiuk/golox/stmt"
 	"github.com/maleksiuk/golox/toks"
 )
 
+// TokenStream is a source of tokens, such as a *scanner.Scanner. Once it runs out of tokens, Next must return
+// an EOF token on every call.
+type TokenStream interface {
+	Next() toks.Token
+}
+
+// sliceStream is a TokenStream over tokens that have already been scanned.
+type sliceStream struct {
+	tokens []toks.Token
+}
+
+func (s *sliceStream) Next() toks.Token {
+	if len(s.tokens) == 0 {
+		return toks.Token{TokenType: toks.EOF}
+	}
+
+	token := s.tokens[0]
+	if token.TokenType != toks.EOF {
+		s.tokens = s.tokens[1:]
+	}
+	return token
+}
+
 type parser struct {
-	current     int
-	tokens      []toks.Token
+	current int
+
+	// tokens holds the tokens read from stream that the parser may still look at: the previous token, the
+	// current one and any that have been looked ahead at.
+	tokens []toks.Token
+	stream TokenStream
+
 	errorReport *errorreport.ErrorReport
+
+	// loopDepth is the number of loops enclosing the statement being parsed. It is used to reject
+	// 'break' and 'continue' statements that are not inside a loop.
+	loopDepth int
+
+	// functionDepth is the number of functions enclosing the statement being parsed. It is used to reject
+	// 'return' statements in top-level code.
+	functionDepth int
 }
 
 type parseError struct {
@@ -75,7 +139,13 @@ func newParseError(token toks.Token, message string) error {
 
 // Parse converts a list of tokens to a list of statements.
 func Parse(tokens []toks.Token, errorReport *errorreport.ErrorReport) []stmt.Stmt {
-	p := parser{current: 0, tokens: tokens, errorReport: errorReport}
+	return ParseStream(&sliceStream{tokens: tokens}, errorReport)
+}
+
+// ParseStream converts the tokens read from stream to a list of statements. Tokens are only read from stream as
+// the parser needs them.
+func ParseStream(stream TokenStream, errorReport *errorreport.ErrorReport) []stmt.Stmt {
+	p := parser{current: 0, stream: stream, errorReport: errorReport}
 
 	var statements []stmt.Stmt
 
@@ -92,11 +162,29 @@ func Parse(tokens []toks.Token, errorReport *errorreport.ErrorReport) []stmt.Stm
 		}
 
 		statements = append(statements, statement)
+		p.discardConsumed()
 	}
 
 	return statements
 }
 
+// tokenAt returns the token at index i of p.tokens, reading tokens from the stream until it has been read.
+func (p *parser) tokenAt(i int) toks.Token {
+	for i >= len(p.tokens) {
+		p.tokens = append(p.tokens, p.stream.Next())
+	}
+
+	return p.tokens[i]
+}
+
+// discardConsumed forgets the tokens before the previous one, which the parser won't look at again.
+func (p *parser) discardConsumed() {
+	if p.current > 1 {
+		p.tokens = p.tokens[p.current-1:]
+		p.current = 1
+	}
+}
+
 func (p *parser) printError(token toks.Token, message string) {
 	if token.TokenType == toks.EOF {
 		p.errorReport.Report(token.Line, "at end", message)
@@ -118,7 +206,17 @@ func (p *parser) declaration() (stmt.Stmt, error) {
 		}
 	}()
 
-	if p.match(toks.Fun) {
+	if p.match(toks.Import) {
+		return p.importDeclaration()
+	}
+
+	if p.match(toks.From) {
+		return p.fromImportDeclaration()
+	}
+
+	// 'fun' followed by '(' starts an anonymous function, which is handled as an expression statement.
+	if p.check(toks.Fun) && p.checkNext(toks.Identifier) {
+		p.advance()
 		return p.funDeclaration()
 	}
 
@@ -130,6 +228,7 @@ func (p *parser) declaration() (stmt.Stmt, error) {
 }
 
 func (p *parser) varDeclaration() (stmt.Stmt, error) {
+	keyword := p.previous()
 	nameToken := p.consume(toks.Identifier, "Expect variable name.")
 
 	var expr expr.Expr
@@ -143,14 +242,59 @@ func (p *parser) varDeclaration() (stmt.Stmt, error) {
 
 	p.consume(toks.Semicolon, "Expect ';' after variable declaration.")
 
-	return &stmt.Var{Name: nameToken, Initializer: expr}, nil
+	return &stmt.Var{Keyword: keyword, Name: nameToken, Initializer: expr}, nil
+}
+
+func (p *parser) importDeclaration() (stmt.Stmt, error) {
+	keyword := p.previous()
+	path := p.consume(toks.String, "Expect module path after 'import'.")
+	p.consume(toks.As, "Expect 'as' after module path.")
+	alias := p.consume(toks.Identifier, "Expect module name after 'as'.")
+	p.consume(toks.Semicolon, "Expect ';' after import.")
+
+	return &stmt.Import{Keyword: keyword, Path: path, Alias: alias}, nil
+}
+
+func (p *parser) fromImportDeclaration() (stmt.Stmt, error) {
+	keyword := p.previous()
+	path := p.consume(toks.String, "Expect module path after 'from'.")
+	p.consume(toks.Import, "Expect 'import' after module path.")
+
+	names := make([]toks.Token, 0, 5)
+	matchedComma := true
+	for matchedComma {
+		names = append(names, p.consume(toks.Identifier, "Expect name to import."))
+		matchedComma = p.match(toks.Comma)
+	}
+
+	p.consume(toks.Semicolon, "Expect ';' after import.")
+
+	return &stmt.Import{Keyword: keyword, Path: path, Names: names}, nil
 }
 
 func (p *parser) funDeclaration() (stmt.Stmt, error) {
+	keyword := p.previous()
 	nameToken := p.consume(toks.Identifier, "Expect function name.")
 
-	p.consume(toks.LeftParen, "Expect '(' after function name")
+	p.consume(toks.LeftParen, "Expect '(' after function name.")
+	parameters, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
 
+	p.consume(toks.LeftBrace, "Expect '{' before function body.")
+	body, err := p.functionBody()
+	if err != nil {
+		return nil, err
+	}
+
+	rightBrace := p.previous()
+
+	return &stmt.Function{Keyword: keyword, Name: nameToken, Params: parameters, Body: body, RightBrace: rightBrace}, nil
+}
+
+// parameters parses a function's parameter list, starting after the opening '(' and ending after the closing ')'.
+func (p *parser) parameters() ([]toks.Token, error) {
 	parameters := make([]toks.Token, 0, 10)
 	matchedComma := true
 
@@ -158,7 +302,7 @@ func (p *parser) funDeclaration() (stmt.Stmt, error) {
 		for matchedComma {
 			if len(parameters) >= 255 {
 				// TODO: This is one that should just be printed out here without stopping parsing.
-				return nil, newParseError(p.peek(), "Cannot have more than 255 parameters.")
+				return nil, newParseError(p.peek(), "Can't have more than 255 parameters.")
 			}
 
 			identifierToken := p.consume(toks.Identifier, "Expect parameter name.")
@@ -168,15 +312,23 @@ func (p *parser) funDeclaration() (stmt.Stmt, error) {
 		}
 	}
 
-	p.consume(toks.RightParen, "Expect ')' after parameters")
+	p.consume(toks.RightParen, "Expect ')' after parameters.")
 
-	p.consume(toks.LeftBrace, "Expect '{' before function body.")
-	body, err := p.block()
-	if err != nil {
-		return nil, err
-	}
+	return parameters, nil
+}
+
+// functionBody parses the block that makes up a function's body, starting after the opening '{'.
+func (p *parser) functionBody() ([]stmt.Stmt, error) {
+	// A loop around the function doesn't make 'break' valid inside the function body.
+	enclosingLoopDepth := p.loopDepth
+	p.loopDepth = 0
+	p.functionDepth++
+	defer func() {
+		p.loopDepth = enclosingLoopDepth
+		p.functionDepth--
+	}()
 
-	return &stmt.Function{Name: nameToken, Params: parameters, Body: body}, nil
+	return p.block()
 }
 
 func (p *parser) statement() (stmt.Stmt, error) {
@@ -196,12 +348,33 @@ func (p *parser) statement() (stmt.Stmt, error) {
 		return p.conditionalStatement()
 	}
 
+	if p.match(toks.Break) {
+		return p.breakStatement()
+	}
+
+	if p.match(toks.Continue) {
+		return p.continueStatement()
+	}
+
+	if p.match(toks.Throw) {
+		return p.throwStatement()
+	}
+
+	if p.match(toks.Try) {
+		return p.tryStatement()
+	}
+
+	if p.match(toks.Return) {
+		return p.returnStatement()
+	}
+
 	if p.match(toks.LeftBrace) {
+		leftBrace := p.previous()
 		statements, err := p.block()
 		if err != nil {
 			return nil, err
 		}
-		return &stmt.Block{Statements: statements}, nil
+		return &stmt.Block{LeftBrace: leftBrace, Statements: statements, RightBrace: p.previous()}, nil
 	}
 
 	return p.expressionStatement()
@@ -224,6 +397,7 @@ func (p *parser) block() ([]stmt.Stmt, error) {
 }
 
 func (p *parser) conditionalStatement() (stmt.Stmt, error) {
+	keyword := p.previous()
 	p.consume(toks.LeftParen, "Expect '(' after 'if'.")
 
 	condition, err := p.expression()
@@ -245,36 +419,141 @@ func (p *parser) conditionalStatement() (stmt.Stmt, error) {
 		}
 	}
 
-	return &stmt.Conditional{Condition: condition, ThenStatement: thenStatement, ElseStatement: elseStatement}, nil
+	return &stmt.Conditional{Keyword: keyword, Condition: condition, ThenStatement: thenStatement, ElseStatement: elseStatement}, nil
+}
+
+func (p *parser) returnStatement() (stmt.Stmt, error) {
+	keyword := p.previous()
+	if p.functionDepth == 0 {
+		p.handleError(keyword, "Can't return from top-level code.")
+	}
+
+	var value expr.Expr
+	if !p.check(toks.Semicolon) {
+		var err error
+		value, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.consume(toks.Semicolon, "Expect ';' after return value.")
+
+	return &stmt.Return{Keyword: keyword, Value: value}, nil
+}
+
+func (p *parser) throwStatement() (stmt.Stmt, error) {
+	keyword := p.previous()
+
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	p.consume(toks.Semicolon, "Expect ';' after thrown value.")
+
+	return &stmt.Throw{Keyword: keyword, Value: value}, nil
+}
+
+func (p *parser) tryStatement() (stmt.Stmt, error) {
+	try := &stmt.Try{Keyword: p.previous()}
+	var err error
+
+	p.consume(toks.LeftBrace, "Expect '{' after 'try'.")
+	try.Body, err = p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(toks.Catch) {
+		p.consume(toks.LeftParen, "Expect '(' after 'catch'.")
+		try.CatchParam = p.consume(toks.Identifier, "Expect error variable name.")
+		p.consume(toks.RightParen, "Expect ')' after error variable name.")
+
+		p.consume(toks.LeftBrace, "Expect '{' after catch clause.")
+		try.CatchBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.match(toks.Finally) {
+		p.consume(toks.LeftBrace, "Expect '{' after 'finally'.")
+		try.FinallyBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if try.CatchBody == nil && try.FinallyBody == nil {
+		err := p.handleError(p.peek(), "Expect 'catch' or 'finally' after try block.")
+		panic(err)
+	}
+	try.RightBrace = p.previous()
+
+	return try, nil
 }
 
 func (p *parser) printStatement() (stmt.Stmt, error) {
+	keyword := p.previous()
 	val, err := p.expression()
 	if err != nil {
 		return nil, err
 	}
 
-	p.consume(toks.Semicolon, "Expect ';' after value")
+	p.consume(toks.Semicolon, "Expect ';' after value.")
 
-	return &stmt.Print{Expression: val}, nil
+	return &stmt.Print{Keyword: keyword, Expression: val}, nil
 }
 
 func (p *parser) whileStatement() (stmt.Stmt, error) {
-	p.consume(toks.LeftParen, "Expect '(' after while")
+	keyword := p.previous()
+	p.consume(toks.LeftParen, "Expect '(' after 'while'.")
 
 	condition, err := p.expression()
 	if err != nil {
 		return nil, err
 	}
 
-	p.consume(toks.RightParen, "Expect ')' after while")
+	p.consume(toks.RightParen, "Expect ')' after condition.")
 
-	body, err := p.statement()
+	body, err := p.loopBody()
 	if err != nil {
 		return nil, err
 	}
 
-	return &stmt.While{Condition: condition, Body: body}, nil
+	return &stmt.While{Keyword: keyword, Condition: condition, Body: body}, nil
+}
+
+func (p *parser) loopBody() (stmt.Stmt, error) {
+	p.loopDepth++
+	defer func() {
+		p.loopDepth--
+	}()
+
+	return p.statement()
+}
+
+func (p *parser) breakStatement() (stmt.Stmt, error) {
+	keyword := p.previous()
+	if p.loopDepth == 0 {
+		p.handleError(keyword, "Cannot use 'break' outside of a loop.")
+	}
+
+	p.consume(toks.Semicolon, "Expect ';' after 'break'.")
+
+	return &stmt.Break{Keyword: keyword}, nil
+}
+
+func (p *parser) continueStatement() (stmt.Stmt, error) {
+	keyword := p.previous()
+	if p.loopDepth == 0 {
+		p.handleError(keyword, "Cannot use 'continue' outside of a loop.")
+	}
+
+	p.consume(toks.Semicolon, "Expect ';' after 'continue'.")
+
+	return &stmt.Continue{Keyword: keyword}, nil
 }
 
 /*
@@ -283,9 +562,10 @@ forStmt   → "for" "(" ( varDecl | exprStmt | ";" )
 					  expression? ")" statement ;
 */
 func (p *parser) forStatement() (stmt.Stmt, error) {
+	keyword := p.previous()
 	var err error
 
-	p.consume(toks.LeftParen, "Expect '(' after for")
+	p.consume(toks.LeftParen, "Expect '(' after 'for'.")
 
 	var initializer stmt.Stmt
 	var condition expr.Expr
@@ -302,14 +582,16 @@ func (p *parser) forStatement() (stmt.Stmt, error) {
 		return nil, err
 	}
 
+	// A missing condition is true, and covers the ';' where it would have been.
 	if p.match(toks.Semicolon) {
-		condition = nil
+		semicolon := p.previous()
+		condition = &expr.Literal{Value: lox.Bool(true), ValuePos: semicolon.Pos, ValueEnd: semicolon.Pos}
 	} else {
 		condition, err = p.expression()
 		if err != nil {
 			return nil, err
 		}
-		p.consume(toks.Semicolon, "Expect ';' after for condition")
+		p.consume(toks.Semicolon, "Expect ';' after loop condition.")
 	}
 
 	if p.match(toks.RightParen) {
@@ -319,27 +601,16 @@ func (p *parser) forStatement() (stmt.Stmt, error) {
 		if err != nil {
 			return nil, err
 		}
-		p.consume(toks.RightParen, "Expect ')' at end of for")
+		p.consume(toks.RightParen, "Expect ')' after for clauses.")
 	}
 
-	userSpecifiedBody, err := p.statement()
+	body, err := p.loopBody()
 	if err != nil {
 		return nil, err
 	}
 
-	// the body is the user-specified body followed by the (optional) increment
-	var bodyStatements = make([]stmt.Stmt, 0, 2)
-	bodyStatements = append(bodyStatements, userSpecifiedBody)
-	if increment != nil {
-		bodyStatements = append(bodyStatements, &stmt.Expression{Expression: increment})
-	}
-	bodyBlock := &stmt.Block{Statements: bodyStatements}
-
-	if condition == nil {
-		condition = &expr.Literal{Value: true}
-	}
-
-	while := &stmt.While{Condition: condition, Body: bodyBlock}
+	// the (optional) increment is kept separate from the body so that it still runs after a 'continue'
+	while := &stmt.While{Keyword: keyword, Condition: condition, Body: body, Increment: increment}
 
 	// the final result is the (optional) initializer followed by the while loop
 	var statements = make([]stmt.Stmt, 0, 2)
@@ -347,7 +618,7 @@ func (p *parser) forStatement() (stmt.Stmt, error) {
 		statements = append(statements, initializer)
 	}
 	statements = append(statements, while)
-	block := &stmt.Block{Statements: statements}
+	block := &stmt.Block{LeftBrace: keyword, Statements: statements}
 
 	return block, nil
 }
@@ -358,7 +629,7 @@ func (p *parser) expressionStatement() (stmt.Stmt, error) {
 		return nil, err
 	}
 
-	p.consume(toks.Semicolon, "Expect ';' after value")
+	p.consume(toks.Semicolon, "Expect ';' after expression.")
 
 	return &stmt.Expression{Expression: val}, nil
 }
@@ -368,7 +639,7 @@ func (p *parser) expression() (expr.Expr, error) {
 }
 
 func (p *parser) previous() toks.Token {
-	return p.tokens[p.current-1]
+	return p.tokenAt(p.current - 1)
 }
 
 func (p *parser) logicAnd() (expr.Expr, error) {
@@ -410,7 +681,7 @@ func (p *parser) logicOr() (expr.Expr, error) {
 }
 
 func (p *parser) assignment() (expr.Expr, error) {
-	expression, err := p.logicOr()
+	expression, err := p.conditional()
 	if err != nil {
 		return nil, err
 	}
@@ -426,19 +697,132 @@ func (p *parser) assignment() (expr.Expr, error) {
 			return &expr.Assign{Name: variable.Name, Value: value}, nil
 		}
 
-		p.handleError(equals, "Invalid assignment target")
+		if index, ok := expression.(*expr.Index); ok {
+			return &expr.SetIndex{Object: index.Object, Bracket: index.Bracket, Index: index.Index, Value: value}, nil
+		}
+
+		p.handleError(equals, "Invalid assignment target.")
+	}
+
+	if p.match(toks.PlusEqual, toks.MinusEqual, toks.StarEqual, toks.SlashEqual, toks.PercentEqual) {
+		operator := p.previous()
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+
+		if isAssignable(expression) {
+			return &expr.CompoundAssign{Target: expression, Operator: operator, Value: value}, nil
+		}
+
+		p.handleError(operator, "Invalid assignment target.")
+	}
+
+	return expression, nil
+}
+
+// isAssignable returns true if the expression can be the target of a compound assignment, increment or decrement.
+func isAssignable(expression expr.Expr) bool {
+	switch expression.(type) {
+	case *expr.Variable, *expr.Index:
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) conditional() (expr.Expr, error) {
+	expression, err := p.logicOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(toks.Question) {
+		thenBranch, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		p.consume(toks.Colon, "Expect ':' after then branch of conditional expression.")
+
+		elseBranch, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+
+		expression = &expr.Conditional{Condition: expression, Then: thenBranch, Else: elseBranch}
 	}
 
 	return expression, nil
 }
 
 func (p *parser) equality() (expr.Expr, error) {
-	expression, err := p.comparison()
+	expression, err := p.bitOr()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.match(toks.BangEqual, toks.EqualEqual) {
+		operator := p.previous()
+		right, err := p.bitOr()
+		if err != nil {
+			return nil, err
+		}
+
+		expression = &expr.Binary{Left: expression, Operator: operator, Right: right}
+	}
+
+	return expression, nil
+}
+
+// The bitwise operators sit between equality and comparison. Among themselves they bind like in C, with & tightest
+// and | loosest, but unlike in C they bind more tightly than == and !=, so that flags & mask == 0 means
+// (flags & mask) == 0.
+func (p *parser) bitOr() (expr.Expr, error) {
+	expression, err := p.bitXor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(toks.Pipe) {
+		operator := p.previous()
+		right, err := p.bitXor()
+		if err != nil {
+			return nil, err
+		}
+
+		expression = &expr.Binary{Left: expression, Operator: operator, Right: right}
+	}
+
+	return expression, nil
+}
+
+func (p *parser) bitXor() (expr.Expr, error) {
+	expression, err := p.bitAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(toks.Caret) {
+		operator := p.previous()
+		right, err := p.bitAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		expression = &expr.Binary{Left: expression, Operator: operator, Right: right}
+	}
+
+	return expression, nil
+}
+
+func (p *parser) bitAnd() (expr.Expr, error) {
+	expression, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(toks.Ampersand) {
 		operator := p.previous()
 		right, err := p.comparison()
 		if err != nil {
@@ -452,12 +836,32 @@ func (p *parser) equality() (expr.Expr, error) {
 }
 
 func (p *parser) comparison() (expr.Expr, error) {
-	expression, err := p.addition()
+	expression, err := p.shift()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.match(toks.Greater, toks.GreaterEqual, toks.Less, toks.LessEqual) {
+		operator := p.previous()
+		right, err := p.shift()
+		if err != nil {
+			return nil, err
+		}
+
+		expression = &expr.Binary{Left: expression, Operator: operator, Right: right}
+	}
+
+	return expression, nil
+}
+
+// Shifts bind more tightly than comparisons and less tightly than addition, as they do in C.
+func (p *parser) shift() (expr.Expr, error) {
+	expression, err := p.addition()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(toks.LessLess, toks.GreaterGreater) {
 		operator := p.previous()
 		right, err := p.addition()
 		if err != nil {
@@ -494,7 +898,7 @@ func (p *parser) multiplication() (expr.Expr, error) {
 		return nil, err
 	}
 
-	for p.match(toks.Star, toks.Slash) {
+	for p.match(toks.Star, toks.Slash, toks.TildeSlash, toks.Percent) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -507,7 +911,7 @@ func (p *parser) multiplication() (expr.Expr, error) {
 }
 
 func (p *parser) unary() (expr.Expr, error) {
-	if p.match(toks.Bang, toks.Minus) {
+	if p.match(toks.Bang, toks.Minus, toks.Tilde) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -517,11 +921,65 @@ func (p *parser) unary() (expr.Expr, error) {
 		return &expr.Unary{Operator: operator, Right: right}, nil
 	}
 
-	return p.call()
+	if p.match(toks.PlusPlus, toks.MinusMinus) {
+		operator := p.previous()
+		target, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAssignable(target) {
+			p.handleError(operator, "Invalid increment or decrement target.")
+			return target, nil
+		}
+
+		return &expr.Update{Target: target, Operator: operator, Prefix: true}, nil
+	}
+
+	return p.exponent()
+}
+
+func (p *parser) exponent() (expr.Expr, error) {
+	expression, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+
+	// '**' is right-associative and binds tighter than a unary operator on its left, so -2 ** 2 is -4,
+	// but its right operand may itself have a unary operator, as in 2 ** -1.
+	if p.match(toks.StarStar) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		expression = &expr.Binary{Left: expression, Operator: operator, Right: right}
+	}
+
+	return expression, nil
+}
+
+func (p *parser) postfix() (expr.Expr, error) {
+	expression, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(toks.PlusPlus, toks.MinusMinus) {
+		operator := p.previous()
+		if !isAssignable(expression) {
+			p.handleError(operator, "Invalid increment or decrement target.")
+			return expression, nil
+		}
+
+		return &expr.Update{Target: expression, Operator: operator, Prefix: false}, nil
+	}
+
+	return expression, nil
 }
 
 func (p *parser) call() (expr.Expr, error) {
-	expr, err := p.primary()
+	expression, err := p.primary()
 	if err != nil {
 		return nil, err
 	}
@@ -529,16 +987,27 @@ func (p *parser) call() (expr.Expr, error) {
 	// the book says doing true/break will be better later on
 	for true {
 		if p.match(toks.LeftParen) {
-			expr, err = p.finishCall(expr)
+			expression, err = p.finishCall(expression)
+			if err != nil {
+				return nil, err
+			}
+		} else if p.match(toks.Dot) {
+			name := p.consume(toks.Identifier, "Expect property name after '.'.")
+			expression = &expr.Get{Object: expression, Name: name}
+		} else if p.match(toks.LeftBracket) {
+			bracket := p.previous()
+			index, err := p.expression()
 			if err != nil {
 				return nil, err
 			}
+			rightBracket := p.consume(toks.RightBracket, "Expect ']' after index.")
+			expression = &expr.Index{Object: expression, Bracket: bracket, Index: index, RightBracket: rightBracket}
 		} else {
 			break
 		}
 	}
 
-	return expr, nil
+	return expression, nil
 }
 
 func (p *parser) finishCall(callee expr.Expr) (expr.Expr, error) {
@@ -550,7 +1019,7 @@ func (p *parser) finishCall(callee expr.Expr) (expr.Expr, error) {
 		for matchedComma {
 			// the book implementation also has >= here even though the error message implies >
 			if len(args) >= 255 {
-				p.handleError(p.peek(), "Cannot have more than 255 arguments.")
+				p.handleError(p.peek(), "Can't have more than 255 arguments.")
 			}
 
 			arg, err := p.expression()
@@ -563,44 +1032,186 @@ func (p *parser) finishCall(callee expr.Expr) (expr.Expr, error) {
 		}
 	}
 
-	rightParenToken := p.consume(toks.RightParen, "Expected ')' to finish function call")
+	rightParenToken := p.consume(toks.RightParen, "Expect ')' after arguments.")
 
 	return &expr.Call{Callee: callee, Paren: rightParenToken, Arguments: args}, nil
 }
 
 func (p *parser) primary() (expr.Expr, error) {
-	if p.match(toks.Number, toks.String) {
-		return &expr.Literal{Value: p.previous().Literal}, nil
+	if p.match(toks.Number) {
+		return literal(p.previous(), lox.FromLiteral(p.previous().Literal)), nil
+	}
+
+	if p.match(toks.String) {
+		return literal(p.previous(), lox.String(p.previous().Literal.(string))), nil
 	}
 
 	if p.match(toks.False) {
-		return &expr.Literal{Value: false}, nil
+		return literal(p.previous(), lox.Bool(false)), nil
 	}
 
 	if p.match(toks.True) {
-		return &expr.Literal{Value: true}, nil
+		return literal(p.previous(), lox.Bool(true)), nil
 	}
 
 	if p.match(toks.Nil) {
-		return &expr.Literal{Value: nil}, nil
+		return literal(p.previous(), lox.Nil), nil
+	}
+
+	if p.match(toks.Fun) {
+		return p.lambda()
+	}
+
+	if p.check(toks.LeftParen) && p.isArrowFunction() {
+		return p.arrowFunction()
 	}
 
 	if p.match(toks.LeftParen) {
+		leftParen := p.previous()
 		expression, err := p.expression()
 		if err != nil {
 			return nil, err
 		}
 
-		p.consume(toks.RightParen, "Expect ')' after expression.")
+		rightParen := p.consume(toks.RightParen, "Expect ')' after expression.")
 
-		return &expr.Grouping{Expression: expression}, nil
+		return &expr.Grouping{LeftParen: leftParen, Expression: expression, RightParen: rightParen}, nil
 	}
 
 	if p.match(toks.Identifier) {
 		return &expr.Variable{Name: p.previous()}, nil
 	}
 
-	return nil, newParseError(p.peek(), "expect expression")
+	if p.match(toks.LeftBracket) {
+		return p.list()
+	}
+
+	if p.match(toks.LeftBrace) {
+		return p.mapLiteral()
+	}
+
+	return nil, newParseError(p.peek(), "Expect expression.")
+}
+
+// literal makes a literal that covers token.
+func literal(token toks.Token, value lox.Value) *expr.Literal {
+	return &expr.Literal{Value: value, ValuePos: token.Pos, ValueEnd: token.End()}
+}
+
+func (p *parser) list() (expr.Expr, error) {
+	bracket := p.previous()
+	elements := make([]expr.Expr, 0, 5)
+
+	if !p.check(toks.RightBracket) {
+		matchedComma := true
+		for matchedComma {
+			element, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+
+			matchedComma = p.match(toks.Comma)
+		}
+	}
+
+	rightBracket := p.consume(toks.RightBracket, "Expect ']' after list elements.")
+
+	return &expr.List{Bracket: bracket, Elements: elements, RightBracket: rightBracket}, nil
+}
+
+func (p *parser) mapLiteral() (expr.Expr, error) {
+	brace := p.previous()
+	keys := make([]expr.Expr, 0, 5)
+	values := make([]expr.Expr, 0, 5)
+
+	if !p.check(toks.RightBrace) {
+		matchedComma := true
+		for matchedComma {
+			key, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			p.consume(toks.Colon, "Expect ':' after map key.")
+
+			value, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			keys = append(keys, key)
+			values = append(values, value)
+
+			matchedComma = p.match(toks.Comma)
+		}
+	}
+
+	rightBrace := p.consume(toks.RightBrace, "Expect '}' after map entries.")
+
+	return &expr.Map{Brace: brace, Keys: keys, Values: values, RightBrace: rightBrace}, nil
+}
+
+func (p *parser) lambda() (expr.Expr, error) {
+	keyword := p.previous()
+
+	p.consume(toks.LeftParen, "Expect '(' after 'fun'.")
+	parameters, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+
+	p.consume(toks.LeftBrace, "Expect '{' before function body.")
+	body, err := p.functionBody()
+	if err != nil {
+		return nil, err
+	}
+
+	return &expr.Function{Keyword: keyword, Params: parameters, Body: stmt.NewFunctionBody(body), BodyEnd: p.previous().End()}, nil
+}
+
+// isArrowFunction looks ahead from a '(' to see whether it starts the parameter list of an arrow function.
+func (p *parser) isArrowFunction() bool {
+	idx := p.current + 1
+
+	if p.tokenAt(idx).TokenType != toks.RightParen {
+		for {
+			if p.tokenAt(idx).TokenType != toks.Identifier {
+				return false
+			}
+			idx++
+
+			if p.tokenAt(idx).TokenType != toks.Comma {
+				break
+			}
+			idx++
+		}
+
+		if p.tokenAt(idx).TokenType != toks.RightParen {
+			return false
+		}
+	}
+
+	return p.tokenAt(idx+1).TokenType == toks.Arrow
+}
+
+func (p *parser) arrowFunction() (expr.Expr, error) {
+	leftParen := p.consume(toks.LeftParen, "Expect '(' before parameters.")
+	parameters, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+
+	arrow := p.consume(toks.Arrow, "Expect '=>' after parameters.")
+
+	// the body is a single expression, so 'break' and 'continue' can't appear in it and 'return' is implied
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	body := []expr.Statement{&stmt.Return{Keyword: arrow, Value: value}}
+
+	return &expr.Function{Keyword: leftParen, Params: parameters, Body: body, BodyEnd: value.End()}, nil
 }
 
 func (p *parser) consume(tokenType toks.TokenType, errorMessage string) toks.Token {
@@ -646,7 +1257,15 @@ func (p *parser) check(tokenType toks.TokenType) bool {
 }
 
 func (p *parser) peek() toks.Token {
-	return p.tokens[p.current]
+	return p.tokenAt(p.current)
+}
+
+func (p *parser) checkNext(tokenType toks.TokenType) bool {
+	if p.isAtEnd() || p.tokenAt(p.current+1).TokenType == toks.EOF {
+		return false
+	}
+
+	return p.tokenAt(p.current+1).TokenType == tokenType
 }
 
 func (p *parser) isAtEnd() bool {
@@ -662,7 +1281,8 @@ func (p *parser) synchronize() {
 		}
 
 		switch p.peek().TokenType {
-		case toks.Class, toks.Fun, toks.Var, toks.For, toks.If, toks.While, toks.Print, toks.Return:
+		case toks.Class, toks.Fun, toks.Var, toks.For, toks.If, toks.While, toks.Print, toks.Return, toks.Break, toks.Continue,
+			toks.Throw, toks.Try, toks.Import:
 			return
 		}
 
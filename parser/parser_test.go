@@ -1,10 +1,13 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/maleksiuk/golox/errorreport"
 	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
+	"github.com/maleksiuk/golox/scanner"
 	"github.com/maleksiuk/golox/stmt"
 	"github.com/maleksiuk/golox/toks"
 	"github.com/maleksiuk/golox/tools"
@@ -52,12 +55,12 @@ func TestParseArithmeticAndComparison(t *testing.T) {
 		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
 		{TokenType: toks.Number, Lexeme: "123.9", Literal: 123.9, Line: 0},
 		{TokenType: toks.Plus, Lexeme: "+", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "92", Literal: 92, Line: 0},
+		{TokenType: toks.Number, Lexeme: "92", Literal: 92.0, Line: 0},
 		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
 		{TokenType: toks.GreaterEqual, Lexeme: ">=", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "5", Literal: 5, Line: 0},
+		{TokenType: toks.Number, Lexeme: "5", Literal: 5.0, Line: 0},
 		{TokenType: toks.Star, Lexeme: "*", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "-9", Literal: -9, Line: 0},
+		{TokenType: toks.Number, Lexeme: "-9", Literal: -9.0, Line: 0},
 		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
 		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
 	}
@@ -93,9 +96,9 @@ func TestParseVariableDeclarations(t *testing.T) {
 		{TokenType: toks.Var, Lexeme: "var", Literal: nil, Line: 0},
 		{TokenType: toks.Identifier, Lexeme: "hello", Literal: nil, Line: 0},
 		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "55", Literal: 55, Line: 0},
+		{TokenType: toks.Number, Lexeme: "55", Literal: 55.0, Line: 0},
 		{TokenType: toks.Plus, Lexeme: "+", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "33", Literal: 33, Line: 0},
+		{TokenType: toks.Number, Lexeme: "33", Literal: 33.0, Line: 0},
 		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
 		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
 	}
@@ -114,9 +117,9 @@ func TestParseVariableAssignments(t *testing.T) {
 	tokens := []toks.Token{
 		{TokenType: toks.Identifier, Lexeme: "hello", Literal: nil, Line: 0},
 		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "55", Literal: 55, Line: 0},
+		{TokenType: toks.Number, Lexeme: "55", Literal: 55.0, Line: 0},
 		{TokenType: toks.Plus, Lexeme: "+", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "33", Literal: 33, Line: 0},
+		{TokenType: toks.Number, Lexeme: "33", Literal: 33.0, Line: 0},
 		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
 		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
 	}
@@ -131,7 +134,7 @@ func TestInvalidAssignmentTargetError(t *testing.T) {
 	tokens := []toks.Token{
 		{TokenType: toks.String, Lexeme: "\"hello\"", Literal: "hello", Line: 0},
 		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "55", Literal: 55, Line: 0},
+		{TokenType: toks.Number, Lexeme: "55", Literal: 55.0, Line: 0},
 		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
 		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
 	}
@@ -140,7 +143,7 @@ func TestInvalidAssignmentTargetError(t *testing.T) {
 	statements := Parse(tokens, &errorReport)
 	expression := statements[0].(*stmt.Expression).Expression
 
-	assertSingleError(t, errorReport, "[line 0] Error at '=': Invalid assignment target\n", true, false)
+	assertSingleError(t, errorReport, "[line 0] Error at '=': Invalid assignment target.\n", true, false)
 	assertAST(t, expression, "hello")
 }
 
@@ -148,14 +151,14 @@ func TestNoSemicolonAfterVariableAssignmentError(t *testing.T) {
 	tokens := []toks.Token{
 		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
 		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "55", Literal: 55, Line: 0},
+		{TokenType: toks.Number, Lexeme: "55", Literal: 55.0, Line: 0},
 		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
 	}
 
 	errorReport := newMockErrorReport()
 	Parse(tokens, &errorReport)
 
-	assertSingleError(t, errorReport, "[line 0] Error at end: Expect ';' after value\n", true, false)
+	assertSingleError(t, errorReport, "[line 0] Error at end: Expect ';' after expression.\n", true, false)
 }
 
 func TestParseLogicalOperators(t *testing.T) {
@@ -163,7 +166,7 @@ func TestParseLogicalOperators(t *testing.T) {
 	tokens := []toks.Token{
 		{TokenType: toks.Identifier, Lexeme: "hello", Literal: nil, Line: 0},
 		{TokenType: toks.EqualEqual, Lexeme: "==", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "55", Literal: 55, Line: 0},
+		{TokenType: toks.Number, Lexeme: "55", Literal: 55.0, Line: 0},
 		{TokenType: toks.Or, Lexeme: "or", Literal: nil, Line: 0},
 		{TokenType: toks.True, Lexeme: "true", Literal: 33, Line: 0},
 		{TokenType: toks.And, Lexeme: "and", Literal: nil, Line: 0},
@@ -189,7 +192,7 @@ func TestParseWhileStatements(t *testing.T) {
 		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
 		{TokenType: toks.Identifier, Lexeme: "something", Literal: nil, Line: 0},
 		{TokenType: toks.EqualEqual, Lexeme: "==", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "3", Literal: 3, Line: 0},
+		{TokenType: toks.Number, Lexeme: "3", Literal: 3.0, Line: 0},
 		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
 		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
 		{TokenType: toks.Print, Lexeme: "print", Literal: nil, Line: 0},
@@ -220,19 +223,19 @@ func TestParseForStatements(t *testing.T) {
 		{TokenType: toks.Var, Lexeme: "var", Literal: nil, Line: 0},
 		{TokenType: toks.Identifier, Lexeme: "x", Literal: nil, Line: 0},
 		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "1", Literal: 1, Line: 0},
+		{TokenType: toks.Number, Lexeme: "1", Literal: 1.0, Line: 0},
 		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
 
 		{TokenType: toks.Identifier, Lexeme: "x", Literal: nil, Line: 0},
 		{TokenType: toks.Less, Lexeme: "<", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "3", Literal: 3, Line: 0},
+		{TokenType: toks.Number, Lexeme: "3", Literal: 3.0, Line: 0},
 		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
 
 		{TokenType: toks.Identifier, Lexeme: "x", Literal: nil, Line: 0},
 		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
 		{TokenType: toks.Identifier, Lexeme: "x", Literal: nil, Line: 0},
 		{TokenType: toks.Plus, Lexeme: "+", Literal: nil, Line: 0},
-		{TokenType: toks.Number, Lexeme: "1", Literal: 1, Line: 0},
+		{TokenType: toks.Number, Lexeme: "1", Literal: 1.0, Line: 0},
 
 		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
 		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
@@ -251,9 +254,8 @@ func TestParseForStatements(t *testing.T) {
 	condition := whileStatement.Condition
 	body := whileStatement.Body
 	bodyStatements := body.(*stmt.Block).Statements
-	userSpecified := bodyStatements[0].(*stmt.Block)
-	printExpression := userSpecified.Statements[0].(*stmt.Print).Expression
-	incrementExpression := bodyStatements[1].(*stmt.Expression).Expression
+	printExpression := bodyStatements[0].(*stmt.Print).Expression
+	incrementExpression := whileStatement.Increment
 
 	assertAST(t, condition, "(< x 3)")
 	assertAST(t, initializerExpression, "1")
@@ -286,13 +288,12 @@ func TestParseEmptyForStatements(t *testing.T) {
 	condition := whileStatement.Condition.(*expr.Literal)
 	body := whileStatement.Body
 	bodyStatements := body.(*stmt.Block).Statements
-	userSpecified := bodyStatements[0].(*stmt.Block)
-	printExpression := userSpecified.Statements[0].(*stmt.Print).Expression
-	if len(bodyStatements) != 1 {
-		t.Error("There should only be one body statement because there is no increment expression.")
+	printExpression := bodyStatements[0].(*stmt.Print).Expression
+	if whileStatement.Increment != nil {
+		t.Error("There should be no increment expression.")
 	}
 
-	if condition.Value != true {
+	if condition.Value != lox.Bool(true) {
 		t.Error("Condition should be a True literal")
 	}
 	assertAST(t, printExpression, "hi")
@@ -374,7 +375,7 @@ func TestFunctionCallArgumentLengthError(t *testing.T) {
 	statements := Parse(tokens, &errorReport)
 	expression := statements[0].(*stmt.Expression).Expression
 
-	assertSingleError(t, errorReport, "[line 0] Error at 'x': Cannot have more than 255 arguments.\n", true, false)
+	assertSingleError(t, errorReport, "[line 0] Error at 'x': Can't have more than 255 arguments.\n", true, false)
 	assertAST(t, expression, "(call somefunction x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x,x)")
 }
 
@@ -394,7 +395,7 @@ func TestFunctionCallNoRightParenError(t *testing.T) {
 		t.Errorf("Expected the parser to return a single nil statement.")
 	}
 
-	assertSingleError(t, errorReport, "[line 0] Error at ';': Expected ')' to finish function call\n", true, false)
+	assertSingleError(t, errorReport, "[line 0] Error at ';': Expect ')' after arguments.\n", true, false)
 }
 
 func TestParseFunctionDeclaration(t *testing.T) {
@@ -455,3 +456,525 @@ func TestParseFunctionDeclarationParameters(t *testing.T) {
 		t.Errorf("Expected parameters to be cool_cool_water and by_marty_robbins")
 	}
 }
+
+func TestParseBreakAndContinueStatements(t *testing.T) {
+	// while (true) { continue; break; }
+	tokens := []toks.Token{
+		{TokenType: toks.While, Lexeme: "while", Literal: nil, Line: 0},
+		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
+		{TokenType: toks.True, Lexeme: "true", Literal: nil, Line: 0},
+		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
+		{TokenType: toks.Continue, Lexeme: "continue", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.Break, Lexeme: "break", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.RightBrace, Lexeme: "}", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	errorReport := newMockErrorReport()
+	statements := Parse(tokens, &errorReport)
+	if errorReport.HadError {
+		t.Errorf("Expected no errors.")
+	}
+
+	bodyStatements := statements[0].(*stmt.While).Body.(*stmt.Block).Statements
+	if _, ok := bodyStatements[0].(*stmt.Continue); !ok {
+		t.Errorf("Expected the first body statement to be a continue statement.")
+	}
+	if _, ok := bodyStatements[1].(*stmt.Break); !ok {
+		t.Errorf("Expected the second body statement to be a break statement.")
+	}
+}
+
+func TestBreakOutsideLoopError(t *testing.T) {
+	tokens := []toks.Token{
+		{TokenType: toks.Break, Lexeme: "break", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	errorReport := newMockErrorReport()
+	Parse(tokens, &errorReport)
+
+	assertSingleError(t, errorReport, "[line 0] Error at 'break': Cannot use 'break' outside of a loop.\n", true, false)
+}
+
+func TestContinueInsideFunctionInsideLoopError(t *testing.T) {
+	// while (true) { fun f() { continue; } }
+	tokens := []toks.Token{
+		{TokenType: toks.While, Lexeme: "while", Literal: nil, Line: 0},
+		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
+		{TokenType: toks.True, Lexeme: "true", Literal: nil, Line: 0},
+		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
+		{TokenType: toks.Fun, Lexeme: "fun", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "f", Literal: nil, Line: 0},
+		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
+		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
+		{TokenType: toks.Continue, Lexeme: "continue", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.RightBrace, Lexeme: "}", Literal: nil, Line: 0},
+		{TokenType: toks.RightBrace, Lexeme: "}", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	errorReport := newMockErrorReport()
+	Parse(tokens, &errorReport)
+
+	assertSingleError(t, errorReport, "[line 0] Error at 'continue': Cannot use 'continue' outside of a loop.\n", true, false)
+}
+
+func TestSynchronizeStopsAtStatementKeywords(t *testing.T) {
+	// after the error in each var declaration, parsing picks up again at the keyword, which reports its own error
+	expected := map[string]string{
+		"var ) break;":    "[line 1] Error at 'break': Cannot use 'break' outside of a loop.\n",
+		"var ) continue;": "[line 1] Error at 'continue': Cannot use 'continue' outside of a loop.\n",
+		"var ) throw;":    "[line 1] Error at ';': Expect expression.\n",
+		"var ) try;":      "[line 1] Error at ';': Expect '{' after 'try'.\n",
+		"var ) import;":   "[line 1] Error at ';': Expect module path after 'import'.\n",
+	}
+
+	for source, message := range expected {
+		errorReport := newMockErrorReport()
+		Parse(scanner.ScanTokens(source, &errorReport), &errorReport)
+
+		errorMessages := errorReport.Printer.(*errorreport.MockPrinter).GetStrings()
+		if len(errorMessages) != 2 || errorMessages[1] != message {
+			t.Errorf("Expected the second error for %q to be %q, but the errors were %q", source, message, errorMessages)
+		}
+	}
+}
+
+func TestParseTryStatements(t *testing.T) {
+	// try { throw "hi"; } catch (e) { print e; }
+	tokens := []toks.Token{
+		{TokenType: toks.Try, Lexeme: "try", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
+		{TokenType: toks.Throw, Lexeme: "throw", Literal: nil, Line: 0},
+		{TokenType: toks.String, Lexeme: "\"hi\"", Literal: "hi", Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.RightBrace, Lexeme: "}", Literal: nil, Line: 0},
+		{TokenType: toks.Catch, Lexeme: "catch", Literal: nil, Line: 0},
+		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "e", Literal: nil, Line: 0},
+		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
+		{TokenType: toks.Print, Lexeme: "print", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "e", Literal: nil, Line: 0},
+		{TokenType: toks.Dot, Lexeme: ".", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "message", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.RightBrace, Lexeme: "}", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	statements := parse(tokens)
+	try := statements[0].(*stmt.Try)
+
+	thrownValue := try.Body[0].(*stmt.Throw).Value
+	assertAST(t, thrownValue, "hi")
+
+	if try.CatchParam.Lexeme != "e" {
+		t.Errorf("Expected catch parameter to be 'e'")
+	}
+
+	printExpression := try.CatchBody[0].(*stmt.Print).Expression
+	assertAST(t, printExpression, "(. e message)")
+
+	if try.FinallyBody != nil {
+		t.Errorf("Expected there to be no finally block")
+	}
+}
+
+func TestTryWithoutCatchOrFinallyError(t *testing.T) {
+	tokens := []toks.Token{
+		{TokenType: toks.Try, Lexeme: "try", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
+		{TokenType: toks.RightBrace, Lexeme: "}", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	errorReport := newMockErrorReport()
+	Parse(tokens, &errorReport)
+
+	assertSingleError(t, errorReport, "[line 0] Error at end: Expect 'catch' or 'finally' after try block.\n", true, false)
+}
+
+func TestParseImportDeclarations(t *testing.T) {
+	// import "lib.lox" as lib; from "lib.lox" import a, b;
+	tokens := []toks.Token{
+		{TokenType: toks.Import, Lexeme: "import", Literal: nil, Line: 0},
+		{TokenType: toks.String, Lexeme: "\"lib.lox\"", Literal: "lib.lox", Line: 0},
+		{TokenType: toks.As, Lexeme: "as", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "lib", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.From, Lexeme: "from", Literal: nil, Line: 0},
+		{TokenType: toks.String, Lexeme: "\"lib.lox\"", Literal: "lib.lox", Line: 0},
+		{TokenType: toks.Import, Lexeme: "import", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.Comma, Lexeme: ",", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "b", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	statements := parse(tokens)
+
+	importAs := statements[0].(*stmt.Import)
+	if importAs.Path.Literal != "lib.lox" || importAs.Alias.Lexeme != "lib" || importAs.Names != nil {
+		t.Errorf("Expected first statement to import lib.lox as lib")
+	}
+
+	fromImport := statements[1].(*stmt.Import)
+	if fromImport.Path.Literal != "lib.lox" || len(fromImport.Names) != 2 {
+		t.Errorf("Expected second statement to import two names from lib.lox")
+	}
+	if fromImport.Names[0].Lexeme != "a" || fromImport.Names[1].Lexeme != "b" {
+		t.Errorf("Expected imported names to be a and b")
+	}
+}
+
+func TestParseAnonymousFunctions(t *testing.T) {
+	// apply(fun (a) { return a; }, (a, b) => a + b);
+	tokens := []toks.Token{
+		{TokenType: toks.Identifier, Lexeme: "apply", Literal: nil, Line: 0},
+		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
+		{TokenType: toks.Fun, Lexeme: "fun", Literal: nil, Line: 0},
+		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
+		{TokenType: toks.Return, Lexeme: "return", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.RightBrace, Lexeme: "}", Literal: nil, Line: 0},
+		{TokenType: toks.Comma, Lexeme: ",", Literal: nil, Line: 0},
+		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.Comma, Lexeme: ",", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "b", Literal: nil, Line: 0},
+		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
+		{TokenType: toks.Arrow, Lexeme: "=>", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.Plus, Lexeme: "+", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "b", Literal: nil, Line: 0},
+		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	statements := parse(tokens)
+	call := statements[0].(*stmt.Expression).Expression.(*expr.Call)
+
+	assertAST(t, call, "(call apply (fun a),(fun a b))")
+
+	arrowBody := stmt.FunctionBody(call.Arguments[1].(*expr.Function))
+	returnValue := arrowBody[0].(*stmt.Return).Value
+	assertAST(t, returnValue, "(+ a b)")
+}
+
+func TestReturnOutsideFunctionError(t *testing.T) {
+	tokens := []toks.Token{
+		{TokenType: toks.Return, Lexeme: "return", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	errorReport := newMockErrorReport()
+	Parse(tokens, &errorReport)
+
+	assertSingleError(t, errorReport, "[line 0] Error at 'return': Can't return from top-level code.\n", true, false)
+}
+
+func TestParseConditionalExpressions(t *testing.T) {
+	// x = a ? b : c ? d : e;
+	tokens := []toks.Token{
+		{TokenType: toks.Identifier, Lexeme: "x", Literal: nil, Line: 0},
+		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.Question, Lexeme: "?", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "b", Literal: nil, Line: 0},
+		{TokenType: toks.Colon, Lexeme: ":", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "c", Literal: nil, Line: 0},
+		{TokenType: toks.Question, Lexeme: "?", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "d", Literal: nil, Line: 0},
+		{TokenType: toks.Colon, Lexeme: ":", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "e", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	statements := parse(tokens)
+	expression := statements[0].(*stmt.Expression).Expression
+
+	assertAST(t, expression, "(= x (?: a b (?: c d e)))")
+}
+
+func TestParseModuloAndExponent(t *testing.T) {
+	// -2 ** 3 ** -1 % 5
+	tokens := []toks.Token{
+		{TokenType: toks.Minus, Lexeme: "-", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "2", Literal: 2.0, Line: 0},
+		{TokenType: toks.StarStar, Lexeme: "**", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "3", Literal: 3.0, Line: 0},
+		{TokenType: toks.StarStar, Lexeme: "**", Literal: nil, Line: 0},
+		{TokenType: toks.Minus, Lexeme: "-", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "1", Literal: 1.0, Line: 0},
+		{TokenType: toks.Percent, Lexeme: "%", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "5", Literal: 5.0, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	statements := parse(tokens)
+	expression := statements[0].(*stmt.Expression).Expression
+
+	assertAST(t, expression, "(% (- (** 2 (** 3 (- 1)))) 5)")
+}
+
+func TestParseBitwiseOperators(t *testing.T) {
+	// 1 | 2 ^ 3 & 4 == 5; ~1 << 2 + 3 < 4 & 5;
+	tokens := []toks.Token{
+		{TokenType: toks.Number, Lexeme: "1", Literal: int64(1), Line: 0},
+		{TokenType: toks.Pipe, Lexeme: "|", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "2", Literal: int64(2), Line: 0},
+		{TokenType: toks.Caret, Lexeme: "^", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "3", Literal: int64(3), Line: 0},
+		{TokenType: toks.Ampersand, Lexeme: "&", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "4", Literal: int64(4), Line: 0},
+		{TokenType: toks.EqualEqual, Lexeme: "==", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "5", Literal: int64(5), Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.Tilde, Lexeme: "~", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "1", Literal: int64(1), Line: 0},
+		{TokenType: toks.LessLess, Lexeme: "<<", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "2", Literal: int64(2), Line: 0},
+		{TokenType: toks.Plus, Lexeme: "+", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "3", Literal: int64(3), Line: 0},
+		{TokenType: toks.Less, Lexeme: "<", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "4", Literal: int64(4), Line: 0},
+		{TokenType: toks.Ampersand, Lexeme: "&", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "5", Literal: int64(5), Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	statements := parse(tokens)
+
+	assertAST(t, statements[0].(*stmt.Expression).Expression, "(== (| 1 (^ 2 (& 3 4))) 5)")
+	assertAST(t, statements[1].(*stmt.Expression).Expression, "(& (< (<< (~ 1) (+ 2 3)) 4) 5)")
+}
+
+func TestParseCompoundAssignmentAndIncrement(t *testing.T) {
+	// a += b++ * --c;
+	tokens := []toks.Token{
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.PlusEqual, Lexeme: "+=", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "b", Literal: nil, Line: 0},
+		{TokenType: toks.PlusPlus, Lexeme: "++", Literal: nil, Line: 0},
+		{TokenType: toks.Star, Lexeme: "*", Literal: nil, Line: 0},
+		{TokenType: toks.MinusMinus, Lexeme: "--", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "c", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	statements := parse(tokens)
+	expression := statements[0].(*stmt.Expression).Expression
+
+	assertAST(t, expression, "(+= a (* (post++ b) (pre-- c)))")
+}
+
+func TestInvalidCompoundAssignmentTargetError(t *testing.T) {
+	tokens := []toks.Token{
+		{TokenType: toks.Number, Lexeme: "1", Literal: 1.0, Line: 0},
+		{TokenType: toks.StarEqual, Lexeme: "*=", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "2", Literal: 2.0, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	errorReport := newMockErrorReport()
+	Parse(tokens, &errorReport)
+
+	assertSingleError(t, errorReport, "[line 0] Error at '*=': Invalid assignment target.\n", true, false)
+}
+
+func TestInvalidIncrementTargetError(t *testing.T) {
+	tokens := []toks.Token{
+		{TokenType: toks.PlusPlus, Lexeme: "++", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "1", Literal: 1.0, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	errorReport := newMockErrorReport()
+	Parse(tokens, &errorReport)
+
+	assertSingleError(t, errorReport, "[line 0] Error at '++': Invalid increment or decrement target.\n", true, false)
+}
+
+func TestParseListsAndIndexes(t *testing.T) {
+	// a[0] = [1, b[2]];
+	tokens := []toks.Token{
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBracket, Lexeme: "[", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "0", Literal: 0.0, Line: 0},
+		{TokenType: toks.RightBracket, Lexeme: "]", Literal: nil, Line: 0},
+		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBracket, Lexeme: "[", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "1", Literal: 1.0, Line: 0},
+		{TokenType: toks.Comma, Lexeme: ",", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "b", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBracket, Lexeme: "[", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "2", Literal: 2.0, Line: 0},
+		{TokenType: toks.RightBracket, Lexeme: "]", Literal: nil, Line: 0},
+		{TokenType: toks.RightBracket, Lexeme: "]", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	statements := parse(tokens)
+	expression := statements[0].(*stmt.Expression).Expression
+
+	assertAST(t, expression, "([]= a 0 (list 1 ([] b 2)))")
+}
+
+func TestParseMapLiteral(t *testing.T) {
+	// var m = {"a": 1, b: {}};
+	tokens := []toks.Token{
+		{TokenType: toks.Var, Lexeme: "var", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "m", Literal: nil, Line: 0},
+		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
+		{TokenType: toks.String, Lexeme: "\"a\"", Literal: "a", Line: 0},
+		{TokenType: toks.Colon, Lexeme: ":", Literal: nil, Line: 0},
+		{TokenType: toks.Number, Lexeme: "1", Literal: 1.0, Line: 0},
+		{TokenType: toks.Comma, Lexeme: ",", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "b", Literal: nil, Line: 0},
+		{TokenType: toks.Colon, Lexeme: ":", Literal: nil, Line: 0},
+		{TokenType: toks.LeftBrace, Lexeme: "{", Literal: nil, Line: 0},
+		{TokenType: toks.RightBrace, Lexeme: "}", Literal: nil, Line: 0},
+		{TokenType: toks.RightBrace, Lexeme: "}", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}
+
+	statements := parse(tokens)
+	initializer := statements[0].(*stmt.Var).Initializer
+
+	assertAST(t, initializer, "(map a 1 b (map))")
+}
+
+// countingStream is a TokenStream that records how many tokens the parser reads.
+type countingStream struct {
+	tokens []toks.Token
+	reads  int
+}
+
+func (s *countingStream) Next() toks.Token {
+	token := s.tokens[s.reads]
+	s.reads++
+	return token
+}
+
+func TestParseStream(t *testing.T) {
+	// var f = (a, b) => a;
+	stream := &countingStream{tokens: []toks.Token{
+		{TokenType: toks.Var, Lexeme: "var", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "f", Literal: nil, Line: 0},
+		{TokenType: toks.Equal, Lexeme: "=", Literal: nil, Line: 0},
+		{TokenType: toks.LeftParen, Lexeme: "(", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.Comma, Lexeme: ",", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "b", Literal: nil, Line: 0},
+		{TokenType: toks.RightParen, Lexeme: ")", Literal: nil, Line: 0},
+		{TokenType: toks.Arrow, Lexeme: "=>", Literal: nil, Line: 0},
+		{TokenType: toks.Identifier, Lexeme: "a", Literal: nil, Line: 0},
+		{TokenType: toks.Semicolon, Lexeme: ";", Literal: nil, Line: 0},
+		{TokenType: toks.EOF, Lexeme: "", Literal: nil, Line: 0},
+	}}
+
+	errorReport := newMockErrorReport()
+	statements := ParseStream(stream, &errorReport)
+
+	if len(statements) != 1 {
+		t.Fatalf("Expected 1 statement, but got %d", len(statements))
+	}
+	function := statements[0].(*stmt.Var).Initializer.(*expr.Function)
+	if len(function.Params) != 2 {
+		t.Errorf("Expected 2 parameters, but got %d", len(function.Params))
+	}
+
+	// The stream must not be read past the EOF token.
+	if stream.reads != len(stream.tokens) {
+		t.Errorf("Expected %d tokens to be read, but %d were", len(stream.tokens), stream.reads)
+	}
+}
+
+// sourceText returns the part of source that r covers.
+func sourceText(source string, r toks.Range) string {
+	offset := func(pos toks.Position) int {
+		lines := strings.SplitAfter(source, "\n")
+		runes := 0
+		for _, line := range lines[:pos.Line-1] {
+			runes += len([]rune(line))
+		}
+		return runes + pos.Column - 1
+	}
+
+	return string([]rune(source)[offset(r.Pos()):offset(r.End())])
+}
+
+func TestNodePositions(t *testing.T) {
+	source := "if (ok) {\n  print (1 + 2) * x[0];\n} else while (true) f(-1, \"s\");\n" +
+		"for (;;) break;\ntry { g = (a) => a; } catch (e) {}"
+
+	errorReport := newMockErrorReport()
+	statements := Parse(scanner.ScanTokens(source, &errorReport), &errorReport)
+	if errorReport.HadError {
+		t.Fatalf("Unexpected errors: %q", errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+	}
+
+	conditional := statements[0].(*stmt.Conditional)
+	block := conditional.ThenStatement.(*stmt.Block)
+	print := block.Statements[0].(*stmt.Print)
+	product := print.Expression.(*expr.Binary)
+	while := conditional.ElseStatement.(*stmt.While)
+	call := while.Body.(*stmt.Expression).Expression.(*expr.Call)
+	forBlock := statements[1].(*stmt.Block)
+	try := statements[2].(*stmt.Try)
+	assign := try.Body[0].(*stmt.Expression).Expression.(*expr.Assign)
+
+	expected := []struct {
+		node toks.Range
+		text string
+	}{
+		{conditional, "if (ok) {\n  print (1 + 2) * x[0];\n} else while (true) f(-1, \"s\")"},
+		{block, "{\n  print (1 + 2) * x[0];\n}"},
+		{print, "print (1 + 2) * x[0]"},
+		{product, "(1 + 2) * x[0]"},
+		{product.Left, "(1 + 2)"},
+		{product.Left.(*expr.Grouping).Expression.(*expr.Binary).Left, "1"},
+		{product.Right, "x[0]"},
+		{while, "while (true) f(-1, \"s\")"},
+		{while.Condition, "true"},
+		{call, "f(-1, \"s\")"},
+		{call.Arguments[0], "-1"},
+		{call.Arguments[1], "\"s\""},
+		{forBlock, "for (;;) break"},
+		{forBlock.Statements[0].(*stmt.While).Condition, ""},
+		{try, "try { g = (a) => a; } catch (e) {}"},
+		{assign, "g = (a) => a"},
+		{assign.Value, "(a) => a"},
+	}
+
+	for _, test := range expected {
+		if actual := sourceText(source, test.node); actual != test.text {
+			t.Errorf("Expected %T to cover %q, but it covered %q", test.node, test.text, actual)
+		}
+	}
+}
@@ -1,47 +1,110 @@
 package scanner
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/maleksiuk/golox/errorreport"
+	"github.com/maleksiuk/golox/lox"
 	"github.com/maleksiuk/golox/srccode"
 	"github.com/maleksiuk/golox/toks"
 )
 
 var keywords = map[string]toks.TokenType{
-	"and":    toks.And,
-	"class":  toks.Class,
-	"else":   toks.Else,
-	"false":  toks.False,
-	"for":    toks.For,
-	"fun":    toks.Fun,
-	"if":     toks.If,
-	"nil":    toks.Nil,
-	"or":     toks.Or,
-	"print":  toks.Print,
-	"return": toks.Return,
-	"super":  toks.Super,
-	"this":   toks.This,
-	"true":   toks.True,
-	"var":    toks.Var,
-	"while":  toks.While,
+	"and":      toks.And,
+	"as":       toks.As,
+	"break":    toks.Break,
+	"catch":    toks.Catch,
+	"class":    toks.Class,
+	"continue": toks.Continue,
+	"else":     toks.Else,
+	"false":    toks.False,
+	"finally":  toks.Finally,
+	"for":      toks.For,
+	"from":     toks.From,
+	"fun":      toks.Fun,
+	"if":       toks.If,
+	"import":   toks.Import,
+	"nil":      toks.Nil,
+	"or":       toks.Or,
+	"print":    toks.Print,
+	"return":   toks.Return,
+	"super":    toks.Super,
+	"this":     toks.This,
+	"throw":    toks.Throw,
+	"true":     toks.True,
+	"try":      toks.Try,
+	"var":      toks.Var,
+	"while":    toks.While,
 }
 
-// ScanTokens extracts tokens from a string of Lox code
-func ScanTokens(sourceStr string, errorReport *errorreport.ErrorReport) []toks.Token {
-	source := srccode.NewSource(sourceStr)
+// Scanner produces the tokens of a Lox program one at a time, reading only as much of the source code as it needs
+// to.
+type Scanner struct {
+	source      srccode.Source
+	errorReport *errorreport.ErrorReport
+
+	// pending holds the tokens from the last lexeme that was scanned which haven't been returned yet.
+	pending []toks.Token
+	next    int
+
+	reportedReadError bool
+}
 
-	// our number of tokens will probably be less than half the source length, so we could revise this later
-	tokens := make([]toks.Token, 0, source.Len()/2)
+// New creates a Scanner that reads Lox code from reader. Errors in the code, and any error from reader, are
+// reported to errorReport.
+func New(reader io.Reader, errorReport *errorreport.ErrorReport) *Scanner {
+	s := &Scanner{source: srccode.NewReaderSource(reader), errorReport: errorReport}
+	skipShebang(&s.source)
+	return s
+}
 
-	for !source.AtEnd() {
-		source.BeginNewLexeme()
-		scanToken(&source, &tokens, errorReport)
+// Next returns the next token. Once the end of the source code is reached it returns an EOF token on every call.
+func (s *Scanner) Next() toks.Token {
+	if s.next < len(s.pending) {
+		token := s.pending[s.next]
+		s.next++
+		return token
 	}
 
-	addToken(&tokens, toks.EOF, nil, &source)
+	// Comments, whitespace and malformed lexemes don't produce a token, so keep going until one does.
+	s.pending, s.next = s.pending[:0], 0
+	for len(s.pending) == 0 && !s.source.AtEnd() {
+		s.source.BeginNewLexeme()
+		scanToken(&s.source, &s.pending, s.errorReport)
+	}
 
-	return tokens
+	if len(s.pending) == 0 {
+		if err := s.source.Err(); err != nil && !s.reportedReadError {
+			s.reportedReadError = true
+			s.errorReport.Report(s.source.CurrentLine(), "", fmt.Sprintf("Could not read source code: %v", err))
+		}
+
+		s.source.BeginNewLexeme()
+		addToken(&s.pending, toks.EOF, nil, &s.source)
+	}
+
+	s.next = 1
+	return s.pending[0]
+}
+
+// ScanTokens extracts tokens from a string of Lox code
+func ScanTokens(sourceStr string, errorReport *errorreport.ErrorReport) []toks.Token {
+	s := New(strings.NewReader(sourceStr), errorReport)
+
+	var tokens []toks.Token
+	for {
+		token := s.Next()
+		tokens = append(tokens, token)
+
+		if token.TokenType == toks.EOF {
+			return tokens
+		}
+	}
 }
 
 func scanToken(source *srccode.Source, tokens *[]toks.Token, errorReport *errorreport.ErrorReport) {
@@ -56,18 +119,68 @@ func scanToken(source *srccode.Source, tokens *[]toks.Token, errorReport *errorr
 		addToken(tokens, toks.LeftBrace, nil, source)
 	case '}':
 		addToken(tokens, toks.RightBrace, nil, source)
+	case '[':
+		addToken(tokens, toks.LeftBracket, nil, source)
+	case ']':
+		addToken(tokens, toks.RightBracket, nil, source)
 	case ',':
 		addToken(tokens, toks.Comma, nil, source)
 	case '.':
-		addToken(tokens, toks.Dot, nil, source)
+		if isDigit(source.Peek()) {
+			errorReport.Report(source.CurrentLine(), "", "Expect digit before '.' in number.")
+			source.Advance()
+			handleNumber(source, tokens, errorReport)
+		} else {
+			addToken(tokens, toks.Dot, nil, source)
+		}
 	case '-':
-		addToken(tokens, toks.Minus, nil, source)
+		if source.Match('-') {
+			addToken(tokens, toks.MinusMinus, nil, source)
+		} else if source.Match('=') {
+			addToken(tokens, toks.MinusEqual, nil, source)
+		} else {
+			addToken(tokens, toks.Minus, nil, source)
+		}
 	case '+':
-		addToken(tokens, toks.Plus, nil, source)
+		if source.Match('+') {
+			addToken(tokens, toks.PlusPlus, nil, source)
+		} else if source.Match('=') {
+			addToken(tokens, toks.PlusEqual, nil, source)
+		} else {
+			addToken(tokens, toks.Plus, nil, source)
+		}
 	case ';':
 		addToken(tokens, toks.Semicolon, nil, source)
 	case '*':
-		addToken(tokens, toks.Star, nil, source)
+		if source.Match('*') {
+			addToken(tokens, toks.StarStar, nil, source)
+		} else if source.Match('=') {
+			addToken(tokens, toks.StarEqual, nil, source)
+		} else {
+			addToken(tokens, toks.Star, nil, source)
+		}
+	case '%':
+		if source.Match('=') {
+			addToken(tokens, toks.PercentEqual, nil, source)
+		} else {
+			addToken(tokens, toks.Percent, nil, source)
+		}
+	case '~':
+		if source.Match('/') {
+			addToken(tokens, toks.TildeSlash, nil, source)
+		} else {
+			addToken(tokens, toks.Tilde, nil, source)
+		}
+	case '&':
+		addToken(tokens, toks.Ampersand, nil, source)
+	case '|':
+		addToken(tokens, toks.Pipe, nil, source)
+	case '^':
+		addToken(tokens, toks.Caret, nil, source)
+	case '?':
+		addToken(tokens, toks.Question, nil, source)
+	case ':':
+		addToken(tokens, toks.Colon, nil, source)
 	case '!':
 		if source.Match('=') {
 			addToken(tokens, toks.BangEqual, nil, source)
@@ -77,17 +190,23 @@ func scanToken(source *srccode.Source, tokens *[]toks.Token, errorReport *errorr
 	case '=':
 		if source.Match('=') {
 			addToken(tokens, toks.EqualEqual, nil, source)
+		} else if source.Match('>') {
+			addToken(tokens, toks.Arrow, nil, source)
 		} else {
 			addToken(tokens, toks.Equal, nil, source)
 		}
 	case '<':
-		if source.Match('=') {
+		if source.Match('<') {
+			addToken(tokens, toks.LessLess, nil, source)
+		} else if source.Match('=') {
 			addToken(tokens, toks.LessEqual, nil, source)
 		} else {
 			addToken(tokens, toks.Less, nil, source)
 		}
 	case '>':
-		if source.Match('=') {
+		if source.Match('>') {
+			addToken(tokens, toks.GreaterGreater, nil, source)
+		} else if source.Match('=') {
 			addToken(tokens, toks.GreaterEqual, nil, source)
 		} else {
 			addToken(tokens, toks.Greater, nil, source)
@@ -98,6 +217,10 @@ func scanToken(source *srccode.Source, tokens *[]toks.Token, errorReport *errorr
 			for source.Peek() != '\n' && !source.AtEnd() {
 				source.Advance()
 			}
+		} else if source.Match('*') {
+			skipBlockComment(source, errorReport)
+		} else if source.Match('=') {
+			addToken(tokens, toks.SlashEqual, nil, source)
 		} else {
 			addToken(tokens, toks.Slash, nil, source)
 		}
@@ -113,21 +236,56 @@ func scanToken(source *srccode.Source, tokens *[]toks.Token, errorReport *errorr
 		if isAlpha(r) {
 			handleIdentifier(source, tokens, errorReport)
 		} else {
-			errorReport.Report(source.CurrentLine(), "", "Unexpected character.")
+			errorReport.Report(source.CurrentLine(), "", fmt.Sprintf("Unexpected character %q (%U).", r, r))
+		}
+	}
+}
+
+// skipShebang skips a first line like "#!/usr/bin/env golox", which lets a script be run as an executable.
+func skipShebang(source *srccode.Source) {
+	if source.Peek() != '#' || source.PeekNext() != '!' {
+		return
+	}
+
+	for source.Peek() != '\n' && !source.AtEnd() {
+		source.Advance()
+	}
+}
+
+// skipBlockComment skips a comment whose opening "/*" has already been consumed. Block comments nest, so code
+// that already contains them can be commented out.
+func skipBlockComment(source *srccode.Source, errorReport *errorreport.ErrorReport) {
+	depth := 1
+	for depth > 0 {
+		if source.AtEnd() {
+			errorReport.Report(source.CurrentLine(), "", "Unterminated block comment.")
+			return
+		}
+
+		switch r := source.Advance(); {
+		case r == '\n':
+			source.IncrementLine()
+		case r == '/' && source.Match('*'):
+			depth++
+		case r == '*' && source.Match('/'):
+			depth--
 		}
 	}
 }
 
+// isDigit reports whether r can start a number literal, which must be written with ASCII digits.
 func isDigit(r rune) bool {
 	return r >= '0' && r <= '9'
 }
 
+// isAlpha reports whether r can start an identifier. Like in Go, identifiers can use letters from any language,
+// so größe and π are valid names.
 func isAlpha(r rune) bool {
-	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+	return unicode.IsLetter(r) || r == '_'
 }
 
 func isAlphaNumeric(r rune) bool {
-	return isAlpha(r) || isDigit(r)
+	return isAlpha(r) || unicode.IsDigit(r)
 }
 
 func handleIdentifier(source *srccode.Source, tokens *[]toks.Token, errorReport *errorreport.ErrorReport) {
@@ -144,29 +302,168 @@ func handleIdentifier(source *srccode.Source, tokens *[]toks.Token, errorReport
 	}
 }
 
-func handleNumber(source *srccode.Source, tokens *[]toks.Token, errorReport *errorreport.ErrorReport) {
-	for isDigit(source.Peek()) {
-		source.Advance()
+// ParseNumber converts a string to a number using the same rules as number literals in Lox code. The whole
+// string must be a valid number literal.
+func ParseNumber(str string) (lox.Value, bool) {
+	source := srccode.NewSource(str)
+	if source.AtEnd() || !isDigit(source.Advance()) {
+		return lox.Nil, false
+	}
+
+	if err := scanNumber(&source); err != nil || !source.AtEnd() {
+		return lox.Nil, false
+	}
+
+	literal, err := numberLiteral(str)
+	if err != nil {
+		return lox.Nil, false
+	}
+
+	return lox.FromLiteral(literal), true
+}
+
+type radix struct {
+	name string
+	base int
+}
+
+// radixes are the bases that can be given with a prefix like the x in 0xFF.
+var radixes = map[rune]radix{
+	'x': {name: "hexadecimal", base: 16},
+	'o': {name: "octal", base: 8},
+	'b': {name: "binary", base: 2},
+}
+
+// isDigitIn reports whether r is a digit in the given base. Letters are digits from 10 on, in either case.
+func isDigitIn(r rune, base int) bool {
+	switch {
+	case r >= '0' && r <= '9':
+		return int(r-'0') < base
+	case r >= 'a' && r <= 'z':
+		return int(r-'a')+10 < base
+	case r >= 'A' && r <= 'Z':
+		return int(r-'A')+10 < base
+	}
+
+	return false
+}
+
+// scanNumber advances over the rest of a number literal whose first digit has already been consumed. It returns
+// an error if the literal is malformed.
+func scanNumber(source *srccode.Source) error {
+	if source.Substring(0, 0) == "0" {
+		prefix := source.Peek()
+		if radix, ok := radixes[prefix]; ok {
+			source.Advance()
+			if !isDigitIn(source.Peek(), radix.base) {
+				return fmt.Errorf("Expect %v digits after '0%c'.", radix.name, prefix)
+			}
+			source.Advance()
+
+			if err := scanDigits(source, radix.base); err != nil {
+				return err
+			}
+			if isAlphaNumeric(source.Peek()) {
+				return fmt.Errorf("Invalid digit '%c' in %v number.", source.Peek(), radix.name)
+			}
+			return nil
+		}
+	}
+
+	if err := scanDigits(source, 10); err != nil {
+		return err
 	}
 
 	// Look for a fractional part.
 	if source.Peek() == '.' && isDigit(source.PeekNext()) {
-		// Consume the "."
+		// Consume the "." and the first digit.
+		source.Advance()
+		source.Advance()
+
+		if err := scanDigits(source, 10); err != nil {
+			return err
+		}
+	}
+
+	// Look for an exponent.
+	if source.Peek() == 'e' || source.Peek() == 'E' {
+		source.Advance()
+		if source.Peek() == '+' || source.Peek() == '-' {
+			source.Advance()
+		}
+
+		if !isDigit(source.Peek()) {
+			return errors.New("Expect digits in exponent.")
+		}
 		source.Advance()
 
-		for isDigit(source.Peek()) {
+		return scanDigits(source, 10)
+	}
+
+	return nil
+}
+
+// scanDigits advances over the rest of a run of digits whose first digit has already been consumed. Digits may
+// be separated by single underscores, like 1_000_000.
+func scanDigits(source *srccode.Source, base int) error {
+	for {
+		if source.Peek() == '_' {
 			source.Advance()
+			if !isDigitIn(source.Peek(), base) {
+				return errors.New("Digit separator '_' must be between digits.")
+			}
+		} else if !isDigitIn(source.Peek(), base) {
+			return nil
+		}
+
+		source.Advance()
+	}
+}
+
+// numberLiteral converts the text of a number literal to an int64, or to a float64 if it has a fractional part
+// or an exponent.
+func numberLiteral(numStr string) (interface{}, error) {
+	digits := strings.Replace(numStr, "_", "", -1)
+
+	base := 10
+	if len(digits) > 2 && digits[0] == '0' {
+		if radix, ok := radixes[rune(digits[1])]; ok {
+			base = radix.base
+			digits = digits[2:]
 		}
 	}
 
-	numStr := source.Substring(0, 0)
-	numValue, err := strconv.ParseFloat(numStr, 64)
+	if base != 10 || !strings.ContainsAny(digits, ".eE") {
+		intValue, err := strconv.ParseInt(digits, base, 64)
+		if err != nil {
+			return nil, errors.New("Integer literal is too large.")
+		}
+		return intValue, nil
+	}
+
+	floatValue, err := strconv.ParseFloat(digits, 64)
 	if err != nil {
-		errorReport.Report(source.CurrentLine(), "", "Could not convert number literal to float.")
-		return
+		return nil, errors.New("Could not convert number literal to float.")
+	}
+	return floatValue, nil
+}
+
+func handleNumber(source *srccode.Source, tokens *[]toks.Token, errorReport *errorreport.ErrorReport) {
+	err := scanNumber(source)
+	if err == nil {
+		var numValue interface{}
+		if numValue, err = numberLiteral(source.Substring(0, 0)); err == nil {
+			addToken(tokens, toks.Number, numValue, source)
+			return
+		}
 	}
 
-	addToken(tokens, toks.Number, numValue, source)
+	errorReport.Report(source.CurrentLine(), "", err.Error())
+
+	// Skip the rest of the malformed literal so that it isn't scanned as more tokens.
+	for isAlphaNumeric(source.Peek()) || (source.Peek() == '.' && isDigit(source.PeekNext())) {
+		source.Advance()
+	}
 }
 
 func handleString(source *srccode.Source, tokens *[]toks.Token, errorReport *errorreport.ErrorReport) {
@@ -192,5 +489,7 @@ func handleString(source *srccode.Source, tokens *[]toks.Token, errorReport *err
 }
 
 func addToken(tokens *[]toks.Token, tokenType toks.TokenType, value interface{}, source *srccode.Source) {
-	*tokens = append(*tokens, toks.Token{TokenType: tokenType, Literal: value, Lexeme: source.Substring(0, 0), Line: source.CurrentLine()})
+	line, column := source.StartPosition()
+	pos := toks.Position{Line: line, Column: column}
+	*tokens = append(*tokens, toks.Token{TokenType: tokenType, Literal: value, Lexeme: source.Substring(0, 0), Line: source.CurrentLine(), Pos: pos})
 }
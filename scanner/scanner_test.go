@@ -1,9 +1,12 @@
 package scanner
 
 import (
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/maleksiuk/golox/errorreport"
+	"github.com/maleksiuk/golox/lox"
 	"github.com/maleksiuk/golox/toks"
 )
 
@@ -41,6 +44,14 @@ func newMockErrorReport() errorreport.ErrorReport {
 	return errorreport.ErrorReport{Printer: errorreport.NewMockPrinter()}
 }
 
+func assertErrorReported(t *testing.T, errorReport errorreport.ErrorReport, expected string) {
+	t.Helper()
+	strs := errorReport.Printer.(*errorreport.MockPrinter).GetStrings()
+	if len(strs) != 1 || strs[0] != expected {
+		t.Errorf("Expected error %q, but got %q", expected, strs)
+	}
+}
+
 func TestScanTokens(t *testing.T) {
 	errorReport := newMockErrorReport()
 	tokens := ScanTokens("()", &errorReport)
@@ -69,10 +80,41 @@ func TestScanTokensWithMultipleCharacters(t *testing.T) {
 	assertTokenLexeme(t, bangEqualTokens[0], "!=")
 }
 
+func TestScanOperators(t *testing.T) {
+	errorReport := newMockErrorReport()
+	tokens := ScanTokens("* ** % ? : =>", &errorReport)
+
+	assertSliceLength(t, tokens, 7)
+	assertTokenType(t, tokens[0], toks.Star)
+	assertTokenType(t, tokens[1], toks.StarStar)
+	assertTokenLexeme(t, tokens[1], "**")
+	assertTokenType(t, tokens[2], toks.Percent)
+	assertTokenType(t, tokens[3], toks.Question)
+	assertTokenType(t, tokens[4], toks.Colon)
+	assertTokenType(t, tokens[5], toks.Arrow)
+}
+
+func TestScanAssignmentOperators(t *testing.T) {
+	errorReport := newMockErrorReport()
+	tokens := ScanTokens("+= -= *= /= %= ++ -- - +", &errorReport)
+
+	assertSliceLength(t, tokens, 10)
+	assertTokenType(t, tokens[0], toks.PlusEqual)
+	assertTokenType(t, tokens[1], toks.MinusEqual)
+	assertTokenType(t, tokens[2], toks.StarEqual)
+	assertTokenType(t, tokens[3], toks.SlashEqual)
+	assertTokenType(t, tokens[4], toks.PercentEqual)
+	assertTokenType(t, tokens[5], toks.PlusPlus)
+	assertTokenLexeme(t, tokens[5], "++")
+	assertTokenType(t, tokens[6], toks.MinusMinus)
+	assertTokenType(t, tokens[7], toks.Minus)
+	assertTokenType(t, tokens[8], toks.Plus)
+}
+
 func TestScanComments(t *testing.T) {
 	errorReport := newMockErrorReport()
 	commentTokens := ScanTokens("// This should be ignored", &errorReport)
-	slashTokens := ScanTokens("/*", &errorReport)
+	slashTokens := ScanTokens("/ *", &errorReport)
 
 	assertSliceLength(t, commentTokens, 1)
 	assertTokenType(t, commentTokens[0], toks.EOF)
@@ -116,12 +158,108 @@ func TestScanStrings(t *testing.T) {
 
 func TestScanNumbers(t *testing.T) {
 	errorReport := newMockErrorReport()
-	tokens := ScanTokens("123 456.78", &errorReport)
-	assertSliceLength(t, tokens, 3)
+	tokens := ScanTokens("123 456.78 9007199254740993", &errorReport)
+	assertSliceLength(t, tokens, 4)
 	assertTokenType(t, tokens[0], toks.Number)
 	assertTokenType(t, tokens[1], toks.Number)
-	assertTokenLiteral(t, tokens[0], 123.0)
+	assertTokenLiteral(t, tokens[0], int64(123))
 	assertTokenLiteral(t, tokens[1], 456.78)
+	assertTokenLiteral(t, tokens[2], int64(9007199254740993))
+}
+
+func TestScanNumberLiteralForms(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected interface{}
+	}{
+		{"0xFF", int64(255)},
+		{"0xdead_beef", int64(0xdeadbeef)},
+		{"0b1010", int64(10)},
+		{"0o17", int64(15)},
+		{"1_000_000", int64(1000000)},
+		{"0", int64(0)},
+		{"1.5e-3", 1.5e-3},
+		{"2E10", 2e10},
+		{"1_0.2_5e+1_0", 10.25e10},
+	}
+
+	for _, test := range tests {
+		errorReport := newMockErrorReport()
+		tokens := ScanTokens(test.source, &errorReport)
+		if errorReport.HadError {
+			t.Errorf("Unexpected error scanning %q: %q", test.source, errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+			continue
+		}
+
+		assertSliceLength(t, tokens, 2)
+		assertTokenType(t, tokens[0], toks.Number)
+		assertTokenLexeme(t, tokens[0], test.source)
+		assertTokenLiteral(t, tokens[0], test.expected)
+	}
+}
+
+func TestScanMalformedNumbers(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"0x", "Expect hexadecimal digits after '0x'."},
+		{"0b;", "Expect binary digits after '0b'."},
+		{"0x_1", "Expect hexadecimal digits after '0x'."},
+		{"1__0", "Digit separator '_' must be between digits."},
+		{"100_", "Digit separator '_' must be between digits."},
+		{"1.5_", "Digit separator '_' must be between digits."},
+		{"0b102", "Invalid digit '2' in binary number."},
+		{"0o8", "Expect octal digits after '0o'."},
+		{"0xFG", "Invalid digit 'G' in hexadecimal number."},
+		{"1e", "Expect digits in exponent."},
+		{"1e+", "Expect digits in exponent."},
+		{".5", "Expect digit before '.' in number."},
+		{"0x8000000000000000", "Integer literal is too large."},
+	}
+
+	for _, test := range tests {
+		errorReport := newMockErrorReport()
+		ScanTokens(test.source, &errorReport)
+
+		assertErrorReported(t, errorReport, "[line 1] Error: "+test.expected+"\n")
+	}
+}
+
+func TestMalformedNumberIsSkipped(t *testing.T) {
+	errorReport := newMockErrorReport()
+	tokens := ScanTokens("1__000abc + 2", &errorReport)
+
+	assertSliceLength(t, tokens, 3)
+	assertTokenType(t, tokens[0], toks.Plus)
+	assertTokenType(t, tokens[1], toks.Number)
+}
+
+func TestScanFloorDivision(t *testing.T) {
+	errorReport := newMockErrorReport()
+	tokens := ScanTokens("7 ~/ 2", &errorReport)
+	assertSliceLength(t, tokens, 4)
+	assertTokenType(t, tokens[1], toks.TildeSlash)
+	assertTokenLexeme(t, tokens[1], "~/")
+}
+
+func TestScanBitwiseOperators(t *testing.T) {
+	errorReport := newMockErrorReport()
+	tokens := ScanTokens("& | ^ ~ << >> <= >=", &errorReport)
+
+	expected := []toks.TokenType{toks.Ampersand, toks.Pipe, toks.Caret, toks.Tilde, toks.LessLess, toks.GreaterGreater,
+		toks.LessEqual, toks.GreaterEqual, toks.EOF}
+	assertSliceLength(t, tokens, len(expected))
+	for idx, tokenType := range expected {
+		assertTokenType(t, tokens[idx], tokenType)
+	}
+}
+
+func TestScanIntegerTooLarge(t *testing.T) {
+	errorReport := newMockErrorReport()
+	ScanTokens("9223372036854775808", &errorReport)
+
+	assertErrorReported(t, errorReport, "[line 1] Error: Integer literal is too large.\n")
 }
 
 func TestScanIdentifiers(t *testing.T) {
@@ -139,6 +277,40 @@ func TestScanKeywords(t *testing.T) {
 	assertTokenType(t, tokens[1], toks.And)
 }
 
+func TestScanUnicodeIdentifiers(t *testing.T) {
+	errorReport := newMockErrorReport()
+	tokens := ScanTokens("größe π 变量1 _x٣", &errorReport)
+
+	if errorReport.HadError {
+		t.Errorf("Unexpected errors: %q", errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+	}
+	assertSliceLength(t, tokens, 5)
+	for idx, lexeme := range []string{"größe", "π", "变量1", "_x٣"} {
+		assertTokenType(t, tokens[idx], toks.Identifier)
+		assertTokenLexeme(t, tokens[idx], lexeme)
+	}
+}
+
+func TestUnexpectedCharacterError(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"@", "[line 1] Error: Unexpected character '@' (U+0040).\n"},
+		{"var x = 1 € 2;", "[line 1] Error: Unexpected character '€' (U+20AC).\n"},
+		{"\"ü\"\n😀", "[line 2] Error: Unexpected character '😀' (U+1F600).\n"},
+		{"\x00", "[line 1] Error: Unexpected character '\\x00' (U+0000).\n"},
+		{"١", "[line 1] Error: Unexpected character '١' (U+0661).\n"},
+	}
+
+	for _, test := range tests {
+		errorReport := newMockErrorReport()
+		ScanTokens(test.source, &errorReport)
+
+		assertErrorReported(t, errorReport, test.expected)
+	}
+}
+
 func TestUnterminatedStringError(t *testing.T) {
 	errorReport := newMockErrorReport()
 	ScanTokens("\"hey man", &errorReport)
@@ -149,3 +321,125 @@ func TestUnterminatedStringError(t *testing.T) {
 		t.Error("Expected error report to not say it had a runtime error.")
 	}
 }
+
+func TestScanBlockComments(t *testing.T) {
+	errorReport := newMockErrorReport()
+	tokens := ScanTokens("1 /* one\n/* nested\n*/ still a comment */ 2 /**/ * 3", &errorReport)
+
+	if errorReport.HadError {
+		t.Errorf("Unexpected errors: %q", errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+	}
+	assertSliceLength(t, tokens, 5)
+	assertTokenLiteral(t, tokens[0], int64(1))
+	assertTokenLiteral(t, tokens[1], int64(2))
+	assertTokenLine(t, tokens[1], 3)
+	assertTokenType(t, tokens[2], toks.Star)
+}
+
+func TestUnterminatedBlockCommentError(t *testing.T) {
+	errorReport := newMockErrorReport()
+	ScanTokens("/* outer /* inner */\n", &errorReport)
+
+	assertErrorReported(t, errorReport, "[line 2] Error: Unterminated block comment.\n")
+}
+
+func TestScanShebang(t *testing.T) {
+	errorReport := newMockErrorReport()
+	tokens := ScanTokens("#!/usr/bin/env golox\nprint 1;", &errorReport)
+
+	if errorReport.HadError {
+		t.Errorf("Unexpected errors: %q", errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+	}
+	assertSliceLength(t, tokens, 4)
+	assertTokenType(t, tokens[0], toks.Print)
+	assertTokenLine(t, tokens[0], 2)
+
+	// A shebang is only allowed on the first line.
+	errorReport = newMockErrorReport()
+	ScanTokens("print 1;\n#!/usr/bin/env golox", &errorReport)
+	if !errorReport.HadError {
+		t.Error("Expected a shebang after the first line to be an error.")
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	valid := map[string]lox.Value{
+		"0": lox.Int(0), "123": lox.Int(123), "456.78": lox.Float(456.78), "0x1F": lox.Int(31), "1_000": lox.Int(1000),
+		"2.5e2": lox.Float(250),
+	}
+	for str, expected := range valid {
+		num, ok := ParseNumber(str)
+		if !ok || num != expected {
+			t.Errorf("Expected ParseNumber(%q) to return %v, but it returned %v, %v", str, expected, num, ok)
+		}
+	}
+
+	for _, str := range []string{"", "-1", ".5", "5.", "1.2.3", "12a", " 1", "0x", "1__0", "1e"} {
+		if num, ok := ParseNumber(str); ok {
+			t.Errorf("Expected ParseNumber(%q) to fail, but it returned %v", str, num)
+		}
+	}
+}
+
+func TestScannerNext(t *testing.T) {
+	errorReport := newMockErrorReport()
+	code := "var größe = \"π\"; // comment\n/* block */ print größe;"
+
+	// Reading one byte at a time splits the multi-byte runes across reads.
+	s := New(iotest.OneByteReader(strings.NewReader(code)), &errorReport)
+	expected := ScanTokens(code, &errorReport)
+
+	for idx, want := range expected {
+		if got := s.Next(); got != want {
+			t.Errorf("Expected token %d to be %v, but it was %v", idx, want, got)
+		}
+	}
+
+	// Once the source is exhausted, every call returns EOF.
+	for idx := 0; idx < 2; idx++ {
+		assertTokenType(t, s.Next(), toks.EOF)
+	}
+
+	if errorReport.HadError {
+		t.Errorf("Unexpected errors: %q", errorReport.Printer.(*errorreport.MockPrinter).GetStrings())
+	}
+}
+
+func TestScannerReadError(t *testing.T) {
+	errorReport := newMockErrorReport()
+	// The first read returns "p" and the second one fails.
+	s := New(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("print 1;"))), &errorReport)
+
+	assertTokenLexeme(t, s.Next(), "p")
+	assertTokenType(t, s.Next(), toks.EOF)
+	assertTokenType(t, s.Next(), toks.EOF)
+	assertErrorReported(t, errorReport, "[line 1] Error: Could not read source code: timeout\n")
+}
+
+func TestTokenPositions(t *testing.T) {
+	errorReport := newMockErrorReport()
+	tokens := ScanTokens("var π = \"a\nb\";\n\tx", &errorReport)
+
+	expected := []struct {
+		pos  toks.Position
+		end  toks.Position
+		line int
+	}{
+		{toks.Position{Line: 1, Column: 1}, toks.Position{Line: 1, Column: 4}, 1},
+		{toks.Position{Line: 1, Column: 5}, toks.Position{Line: 1, Column: 6}, 1},
+		{toks.Position{Line: 1, Column: 7}, toks.Position{Line: 1, Column: 8}, 1},
+		// A string's Line is the line that it ends on.
+		{toks.Position{Line: 1, Column: 9}, toks.Position{Line: 2, Column: 3}, 2},
+		{toks.Position{Line: 2, Column: 3}, toks.Position{Line: 2, Column: 4}, 2},
+		{toks.Position{Line: 3, Column: 2}, toks.Position{Line: 3, Column: 3}, 3},
+		{toks.Position{Line: 3, Column: 3}, toks.Position{Line: 3, Column: 3}, 3},
+	}
+
+	assertSliceLength(t, tokens, len(expected))
+	for idx, token := range tokens {
+		if token.Pos != expected[idx].pos || token.End() != expected[idx].end || token.Line != expected[idx].line {
+			t.Errorf("Expected %q to be at %v-%v on line %d, but it was at %v-%v on line %d", token.Lexeme,
+				expected[idx].pos, expected[idx].end, expected[idx].line, token.Pos, token.End(), token.Line)
+		}
+	}
+}
@@ -1,24 +1,52 @@
 package srccode
 
+import (
+	"bufio"
+	"io"
+)
+
 type sourceLocation struct {
 	Start   int
 	Current int
 	Line    int
+
+	// Column is the column of the current rune, and StartLine and StartColumn are where the lexeme begins.
+	Column      int
+	StartLine   int
+	StartColumn int
 }
 
 type Source struct {
 	runes    []rune
 	location sourceLocation
+
+	// reader supplies more runes on demand when the source is being read lazily. It is nil once it has been
+	// exhausted, or if the whole source was provided up front.
+	reader *bufio.Reader
+	err    error
 }
 
 // NewSource creates a new Source based on the provided source code.
 func NewSource(src string) Source {
 	runes := []rune(src)
-	location := sourceLocation{Line: 1}
+	location := sourceLocation{Line: 1, Column: 1, StartLine: 1, StartColumn: 1}
 	return Source{location: location, runes: runes}
 }
 
-// Len returns the length of the source code.
+// NewReaderSource creates a Source that reads the source code from r as it is needed, rather than all at once.
+// Runes before the start of the current lexeme are discarded, so only the current lexeme is held in memory.
+func NewReaderSource(r io.Reader) Source {
+	location := sourceLocation{Line: 1, Column: 1, StartLine: 1, StartColumn: 1}
+	return Source{location: location, reader: bufio.NewReader(r)}
+}
+
+// Err returns the first error, other than io.EOF, that was encountered while reading the source code.
+func (source *Source) Err() error {
+	return source.err
+}
+
+// Len returns the length of the source code. For a Source created with NewReaderSource it is the number of runes
+// that are currently buffered.
 func (source *Source) Len() int {
 	return len(source.runes)
 }
@@ -28,17 +56,20 @@ func (source *Source) CurrentLine() int {
 	return source.location.Line
 }
 
+// StartPosition returns the line and column where the current lexeme begins. Columns count runes, starting at 1.
+func (source *Source) StartPosition() (line int, column int) {
+	return source.location.StartLine, source.location.StartColumn
+}
+
 // IncrementLine adds one to the line number.
 func (source *Source) IncrementLine() {
 	source.location.Line++
 }
 
-func (location *sourceLocation) atEnd(runes []rune) bool {
-	return location.Current >= len(runes)
-}
-
 func (location *sourceLocation) beginNewLexeme() {
 	location.Start = location.Current
+	location.StartLine = location.Line
+	location.StartColumn = location.Column
 }
 
 // Substring returns a portion of the source based on the start and current positions.
@@ -48,18 +79,55 @@ func (source *Source) Substring(startOffset int, endOffset int) string {
 
 // BeginNewLexeme adjusts our start location to the current location.
 func (source *Source) BeginNewLexeme() {
+	if source.reader != nil {
+		// Nothing before the new lexeme can be asked for again, so let it be garbage collected.
+		source.runes = source.runes[source.location.Current:]
+		source.location.Current = 0
+	}
+
 	source.location.beginNewLexeme()
 }
 
 // AtEnd returns true if we have reached the end of the source code.
 func (source *Source) AtEnd() bool {
-	return source.location.atEnd(source.runes)
+	return !source.available(0)
+}
+
+// available reports whether there is a rune at the given offset from the current location, reading more of the
+// source code if it is needed.
+func (source *Source) available(offset int) bool {
+	for source.location.Current+offset >= len(source.runes) {
+		if source.reader == nil {
+			return false
+		}
+
+		r, _, err := source.reader.ReadRune()
+		if err != nil {
+			if err != io.EOF {
+				source.err = err
+			}
+			source.reader = nil
+			return false
+		}
+
+		source.runes = append(source.runes, r)
+	}
+
+	return true
 }
 
 // Advance returns the current rune and then moves us on to the next rune.
 func (source *Source) Advance() rune {
 	r := source.currentRune()
 	source.location.Current++
+
+	// The caller still has to call IncrementLine for a newline.
+	if r == '\n' {
+		source.location.Column = 1
+	} else {
+		source.location.Column++
+	}
+
 	return r
 }
 
@@ -96,7 +164,7 @@ func (source *Source) Peek() rune {
 
 // PeekNext returns the next rune without advancing.
 func (source *Source) PeekNext() rune {
-	if source.location.Current+1 >= len(source.runes) {
+	if !source.available(1) {
 		return 0
 	}
 
@@ -0,0 +1,5 @@
+// Package stmt defines the statement nodes of the syntax tree. The nodes are generated from
+// tools/generateast/ast.spec, so add or change them there and run go generate.
+package stmt
+
+//go:generate go run ../tools/generateast -spec ../tools/generateast/ast.spec -package stmt
@@ -0,0 +1,30 @@
+package stmt
+
+import "github.com/maleksiuk/golox/expr"
+
+// FunctionBody returns the statements in the body of an anonymous function.
+func FunctionBody(function *expr.Function) []Stmt {
+	if function.Body == nil {
+		return nil
+	}
+
+	body := make([]Stmt, len(function.Body))
+	for idx, statement := range function.Body {
+		// every expr.Statement is a Stmt, because the StatementNode marker is only generated in this package
+		body[idx] = statement.(Stmt)
+	}
+	return body
+}
+
+// NewFunctionBody converts statements to the type that expr.Function holds its body as.
+func NewFunctionBody(statements []Stmt) []expr.Statement {
+	if statements == nil {
+		return nil
+	}
+
+	body := make([]expr.Statement, len(statements))
+	for idx, statement := range statements {
+		body[idx] = statement
+	}
+	return body
+}
@@ -0,0 +1,30 @@
+package toks
+
+import "fmt"
+
+// Position is a place in the source code. Lines and columns start at 1, and columns count runes, so a tab is one
+// column. The zero Position means that there is no position, like for a node that the optimizer made up.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// IsValid reports whether the position refers to a place in the source code.
+func (pos Position) IsValid() bool {
+	return pos.Line > 0
+}
+
+func (pos Position) String() string {
+	if !pos.IsValid() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+}
+
+// Range is implemented by the nodes of the syntax tree. Pos is the position of a node's first character and End
+// is the position just after its last one.
+type Range interface {
+	Pos() Position
+	End() Position
+}
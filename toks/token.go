@@ -8,6 +8,25 @@ type Token struct {
 	Lexeme    string
 	Literal   interface{}
 	Line      int
+
+	// Pos is where the lexeme starts. It can be on an earlier line than Line, which is where a string that
+	// spans several lines ends.
+	Pos Position
+}
+
+// End returns the position just after the token's lexeme.
+func (token Token) End() Position {
+	end := token.Pos
+	for _, r := range token.Lexeme {
+		if r == '\n' {
+			end.Line++
+			end.Column = 1
+		} else {
+			end.Column++
+		}
+	}
+
+	return end
 }
 
 func (token Token) String() string {
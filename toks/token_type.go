@@ -10,6 +10,8 @@ const (
 	RightParen
 	LeftBrace
 	RightBrace
+	LeftBracket
+	RightBracket
 	Comma
 	Dot
 	Minus
@@ -17,16 +19,35 @@ const (
 	Semicolon
 	Slash
 	Star
+	Percent
+	Question
+	Colon
+	Ampersand
+	Pipe
+	Caret
+	Tilde
 
 	// One or two character tokens
 	Bang
 	BangEqual
 	Equal
 	EqualEqual
+	Arrow
 	Greater
 	GreaterEqual
 	Less
 	LessEqual
+	LessLess
+	GreaterGreater
+	StarStar
+	TildeSlash
+	PlusEqual
+	MinusEqual
+	StarEqual
+	SlashEqual
+	PercentEqual
+	PlusPlus
+	MinusMinus
 
 	// Literals
 	Identifier
@@ -35,19 +56,28 @@ const (
 
 	// Keywords
 	And
+	As
+	Break
+	Catch
 	Class
+	Continue
 	Else
 	False
+	Finally
+	From
 	Fun
 	For
 	If
+	Import
 	Nil
 	Or
 	Print
 	Return
 	Super
 	This
+	Throw
 	True
+	Try
 	Var
 	While
 
@@ -12,46 +12,76 @@ func _() {
 	_ = x[RightParen-1]
 	_ = x[LeftBrace-2]
 	_ = x[RightBrace-3]
-	_ = x[Comma-4]
-	_ = x[Dot-5]
-	_ = x[Minus-6]
-	_ = x[Plus-7]
-	_ = x[Semicolon-8]
-	_ = x[Slash-9]
-	_ = x[Star-10]
-	_ = x[Bang-11]
-	_ = x[BangEqual-12]
-	_ = x[Equal-13]
-	_ = x[EqualEqual-14]
-	_ = x[Greater-15]
-	_ = x[GreaterEqual-16]
-	_ = x[Less-17]
-	_ = x[LessEqual-18]
-	_ = x[Identifier-19]
-	_ = x[String-20]
-	_ = x[Number-21]
-	_ = x[And-22]
-	_ = x[Class-23]
-	_ = x[Else-24]
-	_ = x[False-25]
-	_ = x[Fun-26]
-	_ = x[For-27]
-	_ = x[If-28]
-	_ = x[Nil-29]
-	_ = x[Or-30]
-	_ = x[Print-31]
-	_ = x[Return-32]
-	_ = x[Super-33]
-	_ = x[This-34]
-	_ = x[True-35]
-	_ = x[Var-36]
-	_ = x[While-37]
-	_ = x[EOF-38]
+	_ = x[LeftBracket-4]
+	_ = x[RightBracket-5]
+	_ = x[Comma-6]
+	_ = x[Dot-7]
+	_ = x[Minus-8]
+	_ = x[Plus-9]
+	_ = x[Semicolon-10]
+	_ = x[Slash-11]
+	_ = x[Star-12]
+	_ = x[Percent-13]
+	_ = x[Question-14]
+	_ = x[Colon-15]
+	_ = x[Ampersand-16]
+	_ = x[Pipe-17]
+	_ = x[Caret-18]
+	_ = x[Tilde-19]
+	_ = x[Bang-20]
+	_ = x[BangEqual-21]
+	_ = x[Equal-22]
+	_ = x[EqualEqual-23]
+	_ = x[Arrow-24]
+	_ = x[Greater-25]
+	_ = x[GreaterEqual-26]
+	_ = x[Less-27]
+	_ = x[LessEqual-28]
+	_ = x[LessLess-29]
+	_ = x[GreaterGreater-30]
+	_ = x[StarStar-31]
+	_ = x[TildeSlash-32]
+	_ = x[PlusEqual-33]
+	_ = x[MinusEqual-34]
+	_ = x[StarEqual-35]
+	_ = x[SlashEqual-36]
+	_ = x[PercentEqual-37]
+	_ = x[PlusPlus-38]
+	_ = x[MinusMinus-39]
+	_ = x[Identifier-40]
+	_ = x[String-41]
+	_ = x[Number-42]
+	_ = x[And-43]
+	_ = x[As-44]
+	_ = x[Break-45]
+	_ = x[Catch-46]
+	_ = x[Class-47]
+	_ = x[Continue-48]
+	_ = x[Else-49]
+	_ = x[False-50]
+	_ = x[Finally-51]
+	_ = x[From-52]
+	_ = x[Fun-53]
+	_ = x[For-54]
+	_ = x[If-55]
+	_ = x[Import-56]
+	_ = x[Nil-57]
+	_ = x[Or-58]
+	_ = x[Print-59]
+	_ = x[Return-60]
+	_ = x[Super-61]
+	_ = x[This-62]
+	_ = x[Throw-63]
+	_ = x[True-64]
+	_ = x[Try-65]
+	_ = x[Var-66]
+	_ = x[While-67]
+	_ = x[EOF-68]
 }
 
-const _TokenType_name = "LeftParenRightParenLeftBraceRightBraceCommaDotMinusPlusSemicolonSlashStarBangBangEqualEqualEqualEqualGreaterGreaterEqualLessLessEqualIdentifierStringNumberAndClassElseFalseFunForIfNilOrPrintReturnSuperThisTrueVarWhileEOF"
+const _TokenType_name = "LeftParenRightParenLeftBraceRightBraceLeftBracketRightBracketCommaDotMinusPlusSemicolonSlashStarPercentQuestionColonAmpersandPipeCaretTildeBangBangEqualEqualEqualEqualArrowGreaterGreaterEqualLessLessEqualLessLessGreaterGreaterStarStarTildeSlashPlusEqualMinusEqualStarEqualSlashEqualPercentEqualPlusPlusMinusMinusIdentifierStringNumberAndAsBreakCatchClassContinueElseFalseFinallyFromFunForIfImportNilOrPrintReturnSuperThisThrowTrueTryVarWhileEOF"
 
-var _TokenType_index = [...]uint8{0, 9, 19, 28, 38, 43, 46, 51, 55, 64, 69, 73, 77, 86, 91, 101, 108, 120, 124, 133, 143, 149, 155, 158, 163, 167, 172, 175, 178, 180, 183, 185, 190, 196, 201, 205, 209, 212, 217, 220}
+var _TokenType_index = [...]uint16{0, 9, 19, 28, 38, 49, 61, 66, 69, 74, 78, 87, 92, 96, 103, 111, 116, 125, 129, 134, 139, 143, 152, 157, 167, 172, 179, 191, 195, 204, 212, 226, 234, 244, 253, 263, 272, 282, 294, 302, 312, 322, 328, 334, 337, 339, 344, 349, 354, 362, 366, 371, 378, 382, 385, 388, 390, 396, 399, 401, 406, 412, 417, 421, 426, 430, 433, 436, 441, 444}
 
 func (i TokenType) String() string {
 	if i < 0 || i >= TokenType(len(_TokenType_index)-1) {
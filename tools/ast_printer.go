@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
 )
 
 // PrintAst returns a string representation of the AST represented by expression.
@@ -18,42 +19,58 @@ type astPrinter struct {
 }
 
 func (printer astPrinter) Print(expression expr.Expr) string {
-	return expression.Accept(printer).(string)
+	return expression.Accept(printer).AsString()
 }
 
-func (printer astPrinter) VisitBinary(binary *expr.Binary) interface{} {
+func (printer astPrinter) VisitBinary(binary *expr.Binary) lox.Value {
 	return printer.parenthesize(binary.Operator.Lexeme, binary.Left, binary.Right)
 }
 
-func (printer astPrinter) VisitLogical(logical *expr.Logical) interface{} {
+func (printer astPrinter) VisitLogical(logical *expr.Logical) lox.Value {
 	return printer.parenthesize(logical.Operator.Lexeme, logical.Left, logical.Right)
 }
 
-func (printer astPrinter) VisitGrouping(grouping *expr.Grouping) interface{} {
+func (printer astPrinter) VisitConditional(conditional *expr.Conditional) lox.Value {
+	return printer.parenthesize("?:", conditional.Condition, conditional.Then, conditional.Else)
+}
+
+func (printer astPrinter) VisitGrouping(grouping *expr.Grouping) lox.Value {
 	return printer.parenthesize("group", grouping.Expression)
 }
 
-func (printer astPrinter) VisitLiteral(literal *expr.Literal) interface{} {
-	return fmt.Sprintf("%v", literal.Value)
+func (printer astPrinter) VisitLiteral(literal *expr.Literal) lox.Value {
+	return lox.String(literal.Value.String())
 }
 
-func (printer astPrinter) VisitUnary(unary *expr.Unary) interface{} {
+func (printer astPrinter) VisitUnary(unary *expr.Unary) lox.Value {
 	return printer.parenthesize(unary.Operator.Lexeme, unary.Right)
 }
 
-func (printer astPrinter) VisitVariable(v *expr.Variable) interface{} {
-	return v.Name.Lexeme
+func (printer astPrinter) VisitVariable(v *expr.Variable) lox.Value {
+	return lox.String(v.Name.Lexeme)
 }
 
-func (printer astPrinter) VisitAssign(assign *expr.Assign) interface{} {
+func (printer astPrinter) VisitAssign(assign *expr.Assign) lox.Value {
 	return printer.parenthesize("=", assign.Name.Lexeme, assign.Value)
 }
 
-func (printer astPrinter) VisitCall(call *expr.Call) interface{} {
+func (printer astPrinter) VisitCompoundAssign(assign *expr.CompoundAssign) lox.Value {
+	return printer.parenthesize(assign.Operator.Lexeme, assign.Target, assign.Value)
+}
+
+func (printer astPrinter) VisitUpdate(update *expr.Update) lox.Value {
+	if update.Prefix {
+		return printer.parenthesize("pre"+update.Operator.Lexeme, update.Target)
+	}
+
+	return printer.parenthesize("post"+update.Operator.Lexeme, update.Target)
+}
+
+func (printer astPrinter) VisitCall(call *expr.Call) lox.Value {
 	var args strings.Builder
 
 	for idx, ele := range call.Arguments {
-		args.WriteString(ele.Accept(printer).(string))
+		args.WriteString(ele.Accept(printer).AsString())
 		if idx != len(call.Arguments)-1 {
 			args.WriteString(",")
 		}
@@ -66,7 +83,46 @@ func (printer astPrinter) VisitCall(call *expr.Call) interface{} {
 	}
 }
 
-func (printer astPrinter) parenthesize(name string, parts ...interface{}) string {
+func (printer astPrinter) VisitGet(get *expr.Get) lox.Value {
+	return printer.parenthesize(".", get.Object, get.Name.Lexeme)
+}
+
+func (printer astPrinter) VisitFunction(function *expr.Function) lox.Value {
+	params := make([]interface{}, len(function.Params))
+	for idx, param := range function.Params {
+		params[idx] = param.Lexeme
+	}
+
+	return printer.parenthesize("fun", params...)
+}
+
+func (printer astPrinter) VisitList(list *expr.List) lox.Value {
+	elements := make([]interface{}, len(list.Elements))
+	for idx, element := range list.Elements {
+		elements[idx] = element
+	}
+
+	return printer.parenthesize("list", elements...)
+}
+
+func (printer astPrinter) VisitMap(m *expr.Map) lox.Value {
+	entries := make([]interface{}, 0, 2*len(m.Keys))
+	for idx, key := range m.Keys {
+		entries = append(entries, key, m.Values[idx])
+	}
+
+	return printer.parenthesize("map", entries...)
+}
+
+func (printer astPrinter) VisitIndex(index *expr.Index) lox.Value {
+	return printer.parenthesize("[]", index.Object, index.Index)
+}
+
+func (printer astPrinter) VisitSetIndex(setIndex *expr.SetIndex) lox.Value {
+	return printer.parenthesize("[]=", setIndex.Object, setIndex.Index, setIndex.Value)
+}
+
+func (printer astPrinter) parenthesize(name string, parts ...interface{}) lox.Value {
 	var str strings.Builder
 
 	str.WriteString("(")
@@ -76,7 +132,7 @@ func (printer astPrinter) parenthesize(name string, parts ...interface{}) string
 		str.WriteString(" ")
 		switch p := part.(type) {
 		case expr.Expr:
-			str.WriteString(p.Accept(printer).(string))
+			str.WriteString(p.Accept(printer).AsString())
 		case string:
 			str.WriteString(p)
 		case fmt.Stringer:
@@ -85,5 +141,5 @@ func (printer astPrinter) parenthesize(name string, parts ...interface{}) string
 	}
 	str.WriteString(")")
 
-	return str.String()
+	return lox.String(str.String())
 }
@@ -4,14 +4,15 @@ import (
 	"testing"
 
 	"github.com/maleksiuk/golox/expr"
+	"github.com/maleksiuk/golox/lox"
 	"github.com/maleksiuk/golox/toks"
 )
 
 func TestPrintAst(t *testing.T) {
 	minus := toks.Token{TokenType: toks.Minus, Lexeme: "-"}
 	star := toks.Token{TokenType: toks.Star, Lexeme: "*"}
-	grouping := expr.Grouping{Expression: &expr.Literal{Value: 45.67}}
-	unary := expr.Unary{Operator: minus, Right: &expr.Literal{Value: 123}}
+	grouping := expr.Grouping{Expression: &expr.Literal{Value: lox.Float(45.67)}}
+	unary := expr.Unary{Operator: minus, Right: &expr.Literal{Value: lox.Float(123)}}
 	binary := expr.Binary{Left: &unary, Operator: star, Right: &grouping}
 
 	str := PrintAst(&binary)
@@ -21,3 +22,18 @@ func TestPrintAst(t *testing.T) {
 		t.Errorf("AstPrinter.Print() = %v, want %v", str, expected)
 	}
 }
+
+func TestPrintAstConditional(t *testing.T) {
+	starStar := toks.Token{TokenType: toks.StarStar, Lexeme: "**"}
+	percent := toks.Token{TokenType: toks.Percent, Lexeme: "%"}
+	power := expr.Binary{Left: &expr.Literal{Value: lox.Float(2)}, Operator: starStar, Right: &expr.Literal{Value: lox.Float(8)}}
+	modulo := expr.Binary{Left: &power, Operator: percent, Right: &expr.Literal{Value: lox.Float(3)}}
+	conditional := expr.Conditional{Condition: &expr.Literal{Value: lox.Bool(true)}, Then: &modulo, Else: &expr.Literal{Value: lox.Nil}}
+
+	str := PrintAst(&conditional)
+
+	expected := "(?: true (% (** 2 8) 3) nil)"
+	if str != expected {
+		t.Errorf("AstPrinter.Print() = %v, want %v", str, expected)
+	}
+}
@@ -0,0 +1,92 @@
+# ast.spec describes the nodes of the syntax tree. generateast turns each package section into a Go file with a
+# struct, an Accept method and Pos and End methods for every node, a Visitor interface and a BaseVisitor that does
+# nothing.
+#
+# A section starts with "package <name> <node interface> [embed=<interface>] [marker=<method>]
+# [visit=<method prefix>] [result=<type>]". The node interface embeds the given interface, which should include
+# toks.Range. Every node gets the marker method with an empty body. The visitor methods are named after the prefix
+# and the node, and return the result type if there is one.
+#
+# Every other line is a node: "<Name> [<pos>..<end>]: <Field> <Type>, <Field> <Type>, ...". Pos returns the start
+# of the <pos> field and End returns the end of the <end> field. A token starts at its first character, a list at
+# its first element and ends at its last one, and a toks.Position is used as it is. Either side can list fields to
+# fall back on, like "Value|Keyword": the first one that is present (not nil, not empty and with a valid position)
+# is used. A field can also be guarded by a bool field, like "Prefix?Operator", to be used when the bool is true.
+# The last field of a side is always used when none of the others are.
+#
+# A node's children, which ast.Walk and ast.Rewrite visit in the order of the fields, are its fields whose type is
+# a node interface (like Expr or stmt.Stmt), an interface that the nodes of another section embed (like Statement)
+# or a list of them. generateast -traversal writes the code for that to the ast package.
+#
+# Lines starting with "//" just above a node become its doc comment, and lines starting with "#" are ignored.
+
+package expr Expr embed=toks.Range result=lox.Value
+
+Binary [Left..Right]: Left Expr, Operator toks.Token, Right Expr
+Logical [Left..Right]: Left Expr, Operator toks.Token, Right Expr
+Conditional [Condition..Else]: Condition Expr, Then Expr, Else Expr
+Grouping [LeftParen..RightParen]: LeftParen toks.Token, Expression Expr, RightParen toks.Token
+
+// Literal covers the source code from ValuePos to ValueEnd. A literal that the optimizer folded covers the
+// expression that it replaced.
+Literal [ValuePos..ValueEnd]: Value lox.Value, ValuePos toks.Position, ValueEnd toks.Position
+
+Unary [Operator..Right]: Operator toks.Token, Right Expr
+
+// Variable and Assign have a nil Local until the resolver finds that they refer to a local variable. Globals keep
+// a nil Local and are looked up by name.
+Variable [Name..Name]: Name toks.Token, Local *Local
+Assign [Name..Value]: Name toks.Token, Value Expr, Local *Local
+
+// CompoundAssign is an assignment such as 'a += 1'. Operator is the compound assignment token (e.g., '+=').
+CompoundAssign [Target..Value]: Target Expr, Operator toks.Token, Value Expr
+
+// Update is an increment or decrement ('++' or '--'), either before (Prefix) or after its target.
+Update [Prefix?Operator|Target..Prefix?Target|Operator]: Target Expr, Operator toks.Token, Prefix bool
+
+// A call ends with its closing parenthesis, which is Paren.
+Call [Callee..Paren]: Callee Expr, Paren toks.Token, Arguments []Expr
+Get [Object..Name]: Object Expr, Name toks.Token
+
+// Function is an anonymous function, either 'fun (params) { body }' or '(params) => expression'. Keyword is
+// 'fun' or the '(' that starts an arrow function. The statements in Body are stmt.Stmt nodes, which are converted
+// with stmt.FunctionBody and stmt.NewFunctionBody. BodyEnd is the position just after the closing brace, or after
+// the expression of an arrow function.
+Function [Keyword..BodyEnd]: Keyword toks.Token, Params []toks.Token, Body []Statement, BodyEnd toks.Position
+
+List [Bracket..RightBracket]: Bracket toks.Token, Elements []Expr, RightBracket toks.Token
+Map [Brace..RightBrace]: Brace toks.Token, Keys []Expr, Values []Expr, RightBrace toks.Token
+Index [Object..RightBracket]: Object Expr, Bracket toks.Token, Index Expr, RightBracket toks.Token
+SetIndex [Object..Value]: Object Expr, Bracket toks.Token, Index Expr, Value Expr
+
+package stmt Stmt embed=expr.Statement marker=StatementNode visit=VisitStatement
+
+# Like in go/ast, a statement's range doesn't include the semicolon that ends it.
+
+Expression [Expression..Expression]: Expression expr.Expr
+
+// The block that a for loop is wrapped in has the 'for' keyword as its LeftBrace and no RightBrace, and the
+// empty block that the optimizer puts in place of a removed statement has neither.
+Block [LeftBrace..RightBrace|Statements|LeftBrace]: LeftBrace toks.Token, Statements []Stmt, RightBrace toks.Token
+
+Conditional [Keyword..ElseStatement|ThenStatement]: Keyword toks.Token, Condition expr.Expr, ThenStatement Stmt, ElseStatement Stmt
+Function [Keyword..RightBrace]: Keyword toks.Token, Name toks.Token, Params []toks.Token, Body []Stmt, RightBrace toks.Token
+Print [Keyword..Expression]: Keyword toks.Token, Expression expr.Expr
+
+// Keyword is 'while', or 'for' for a for loop.
+While [Keyword..Body]: Keyword toks.Token, Condition expr.Expr, Body Stmt, Increment expr.Expr
+
+Var [Keyword..Initializer|Name]: Keyword toks.Token, Name toks.Token, Initializer expr.Expr
+Break [Keyword..Keyword]: Keyword toks.Token
+Continue [Keyword..Keyword]: Keyword toks.Token
+Throw [Keyword..Value]: Keyword toks.Token, Value expr.Expr
+
+// Try has a nil CatchBody when there is no catch clause and a nil FinallyBody when there is no finally
+// clause. The parser ensures that at least one of them is present. RightBrace closes the last clause.
+Try [Keyword..RightBrace]: Keyword toks.Token, Body []Stmt, CatchParam toks.Token, CatchBody []Stmt, FinallyBody []Stmt, RightBrace toks.Token
+
+// Import is either 'import "path" as Alias;' or 'from "path" import Names;'. Names is nil for the first form.
+Import [Keyword..Names|Alias]: Keyword toks.Token, Path toks.Token, Alias toks.Token, Names []toks.Token
+
+// The return statement of an arrow function's body has the '=>' token as its Keyword.
+Return [Keyword..Value|Keyword]: Keyword toks.Token, Value expr.Expr
@@ -0,0 +1,535 @@
+// Command generateast writes the Go code for the syntax tree nodes described in ast.spec, like jlox's GenerateAst.
+// It is run by go generate in the expr and stmt packages:
+//
+//	generateast -spec ../tools/generateast/ast.spec -package expr
+//
+// writes expr.go to the current directory. With -traversal, it writes the code that the ast package uses to visit
+// the children of every node in the spec instead:
+//
+//	generateast -spec ../tools/generateast/ast.spec -traversal -package ast -out children.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const modulePath = "github.com/maleksiuk/golox"
+
+// packageSpec is one package section of the spec.
+type packageSpec struct {
+	name        string
+	nodeType    string
+	embed       string
+	marker      string
+	visitPrefix string
+	result      string
+	nodes       []nodeSpec
+}
+
+type nodeSpec struct {
+	name   string
+	doc    []string
+	fields []fieldSpec
+	pos    []rangeField
+	end    []rangeField
+}
+
+type fieldSpec struct {
+	name     string
+	typeName string
+}
+
+// rangeField is one of the fields that a node's Pos or End can come from. The first one whose guard is true, or
+// that is present if it has no guard, is used.
+type rangeField struct {
+	guard string
+	field fieldSpec
+}
+
+func main() {
+	specPath := flag.String("spec", "ast.spec", "path of the node spec")
+	pkgName := flag.String("package", "", "package to generate")
+	outPath := flag.String("out", "", "file to write (default <package>.go)")
+	traversal := flag.Bool("traversal", false, "generate the child traversal of all the nodes")
+	flag.Parse()
+
+	if *outPath == "" {
+		*outPath = *pkgName + ".go"
+	}
+
+	file, err := os.Open(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	packages, err := parseSpec(file)
+	if err != nil {
+		log.Fatalf("%v:%v", *specPath, err)
+	}
+
+	if *traversal {
+		src, err := generateTraversal(*pkgName, packages)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(*outPath, src, 0644); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	for _, pkg := range packages {
+		if pkg.name != *pkgName {
+			continue
+		}
+
+		src, err := generate(pkg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := ioutil.WriteFile(*outPath, src, 0644); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	log.Fatalf("%v has no package %q", *specPath, *pkgName)
+}
+
+// parseSpec reads the package sections of a spec. The format is described at the top of ast.spec.
+func parseSpec(r io.Reader) ([]packageSpec, error) {
+	var packages []packageSpec
+	var doc []string
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			doc = nil
+		case strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "//"):
+			doc = append(doc, line)
+		case strings.HasPrefix(line, "package "):
+			pkg, err := parsePackage(line)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %v", lineNum, err)
+			}
+			packages = append(packages, pkg)
+			doc = nil
+		default:
+			if len(packages) == 0 {
+				return nil, fmt.Errorf("%d: node before the first package line", lineNum)
+			}
+
+			node, err := parseNode(line)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %v", lineNum, err)
+			}
+			node.doc = doc
+			doc = nil
+
+			pkg := &packages[len(packages)-1]
+			pkg.nodes = append(pkg.nodes, node)
+		}
+	}
+
+	return packages, scanner.Err()
+}
+
+func parsePackage(line string) (packageSpec, error) {
+	words := strings.Fields(line)
+	if len(words) < 3 {
+		return packageSpec{}, fmt.Errorf("expect 'package <name> <node interface>'")
+	}
+
+	pkg := packageSpec{name: words[1], nodeType: words[2], visitPrefix: "Visit"}
+	for _, option := range words[3:] {
+		switch {
+		case strings.HasPrefix(option, "embed="):
+			pkg.embed = strings.TrimPrefix(option, "embed=")
+		case strings.HasPrefix(option, "marker="):
+			pkg.marker = strings.TrimPrefix(option, "marker=")
+		case strings.HasPrefix(option, "visit="):
+			pkg.visitPrefix = strings.TrimPrefix(option, "visit=")
+		case strings.HasPrefix(option, "result="):
+			pkg.result = strings.TrimPrefix(option, "result=")
+		default:
+			return packageSpec{}, fmt.Errorf("unknown package option %q", option)
+		}
+	}
+
+	return pkg, nil
+}
+
+func parseNode(line string) (nodeSpec, error) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return nodeSpec{}, fmt.Errorf("expect ':' after node name")
+	}
+
+	head := strings.Fields(line[:colon])
+	if len(head) == 0 {
+		return nodeSpec{}, fmt.Errorf("expect a node name before ':'")
+	}
+
+	node := nodeSpec{name: head[0]}
+	if !token.IsExported(node.name) {
+		return nodeSpec{}, fmt.Errorf("node name %q must be exported", node.name)
+	}
+
+	for _, field := range strings.Split(line[colon+1:], ",") {
+		words := strings.Fields(field)
+		if len(words) != 2 {
+			return nodeSpec{}, fmt.Errorf("expect '<Field> <Type>' in %v, got %q", node.name, strings.TrimSpace(field))
+		}
+		node.fields = append(node.fields, fieldSpec{name: words[0], typeName: words[1]})
+	}
+
+	rangeSpec := strings.Join(head[1:], "")
+	if !strings.HasPrefix(rangeSpec, "[") || !strings.HasSuffix(rangeSpec, "]") || !strings.Contains(rangeSpec, "..") {
+		return nodeSpec{}, fmt.Errorf("expect '[<pos>..<end>]' after %v", node.name)
+	}
+	bounds := strings.SplitN(rangeSpec[1:len(rangeSpec)-1], "..", 2)
+
+	var err error
+	if node.pos, err = parseRangeFields(node, bounds[0]); err != nil {
+		return nodeSpec{}, err
+	}
+	if node.end, err = parseRangeFields(node, bounds[1]); err != nil {
+		return nodeSpec{}, err
+	}
+
+	return node, nil
+}
+
+// parseRangeFields parses one side of a node's range, such as "Prefix?Operator|Target".
+func parseRangeFields(node nodeSpec, spec string) ([]rangeField, error) {
+	var fields []rangeField
+	for _, alternative := range strings.Split(spec, "|") {
+		var guard string
+		if question := strings.Index(alternative, "?"); question >= 0 {
+			guard, alternative = alternative[:question], alternative[question+1:]
+			if guardField, ok := node.field(guard); !ok || guardField.typeName != "bool" {
+				return nil, fmt.Errorf("%v has no bool field %q", node.name, guard)
+			}
+		}
+
+		field, ok := node.field(alternative)
+		if !ok {
+			return nil, fmt.Errorf("%v has no field %q", node.name, alternative)
+		}
+		fields = append(fields, rangeField{guard: guard, field: field})
+	}
+
+	if fields[len(fields)-1].guard != "" {
+		return nil, fmt.Errorf("the last field in the range of %v must not have a guard", node.name)
+	}
+
+	return fields, nil
+}
+
+func (node nodeSpec) field(name string) (fieldSpec, bool) {
+	for _, field := range node.fields {
+		if field.name == name {
+			return field, true
+		}
+	}
+
+	return fieldSpec{}, false
+}
+
+// qualifier matches the package name in a type such as []toks.Token.
+var qualifier = regexp.MustCompile(`([a-z]+)\.`)
+
+// generate returns the formatted source code of pkg.
+func generate(pkg packageSpec) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by tools/generateast from ast.spec. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %v\n\n", pkg.name)
+
+	imports := map[string]bool{"toks": true}
+	for _, match := range qualifier.FindAllStringSubmatch(pkg.embed+" "+pkg.result, -1) {
+		imports[match[1]] = true
+	}
+	for _, node := range pkg.nodes {
+		for _, field := range node.fields {
+			for _, match := range qualifier.FindAllStringSubmatch(field.typeName, -1) {
+				imports[match[1]] = true
+			}
+		}
+	}
+	if len(imports) > 0 {
+		var paths []string
+		for name := range imports {
+			paths = append(paths, fmt.Sprintf("%q", modulePath+"/"+name))
+		}
+		sort.Strings(paths)
+		fmt.Fprintf(&buf, "import (\n%v\n)\n\n", strings.Join(paths, "\n"))
+	}
+
+	result := ""
+	if pkg.result != "" {
+		result = " " + pkg.result
+	}
+
+	fmt.Fprintf(&buf, "type %v interface {\n", pkg.nodeType)
+	if pkg.embed != "" {
+		fmt.Fprintf(&buf, "%v\n", pkg.embed)
+	}
+	fmt.Fprintf(&buf, "Accept(visitor Visitor)%v\n}\n\n", result)
+
+	for _, node := range pkg.nodes {
+		for _, line := range node.doc {
+			fmt.Fprintf(&buf, "%v\n", line)
+		}
+		fmt.Fprintf(&buf, "type %v struct {\n", node.name)
+		for _, field := range node.fields {
+			fmt.Fprintf(&buf, "%v %v\n", field.name, field.typeName)
+		}
+		fmt.Fprintf(&buf, "}\n\n")
+
+		receiver := receiverName(node.name)
+		if pkg.marker != "" {
+			fmt.Fprintf(&buf, "func (*%v) %v() {}\n\n", node.name, pkg.marker)
+		}
+		fmt.Fprintf(&buf, "func (%v *%v) Accept(visitor Visitor)%v {\n", receiver, node.name, result)
+		if pkg.result != "" {
+			fmt.Fprintf(&buf, "return ")
+		}
+		fmt.Fprintf(&buf, "visitor.%v%v(%v)\n}\n\n", pkg.visitPrefix, node.name, receiver)
+
+		writeRangeMethod(&buf, node, "Pos", node.pos)
+		writeRangeMethod(&buf, node, "End", node.end)
+	}
+
+	fmt.Fprintf(&buf, "type Visitor interface {\n")
+	for _, node := range pkg.nodes {
+		fmt.Fprintf(&buf, "%v%v(%v *%v)%v\n", pkg.visitPrefix, node.name, receiverName(node.name), node.name, result)
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// BaseVisitor is a Visitor whose methods do nothing. Embed it in a visitor that only needs to handle some of\n")
+	fmt.Fprintf(&buf, "// the nodes.\n")
+	fmt.Fprintf(&buf, "type BaseVisitor struct{}\n\n")
+	for _, node := range pkg.nodes {
+		fmt.Fprintf(&buf, "func (BaseVisitor) %v%v(%v *%v)%v {", pkg.visitPrefix, node.name, receiverName(node.name), node.name, result)
+		if pkg.result != "" {
+			fmt.Fprintf(&buf, "\nvar zero %v\nreturn zero\n", pkg.result)
+		}
+		fmt.Fprintf(&buf, "}\n\n")
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// childType is the type of a node field that Walk and Rewrite traverse, either a node interface or a list of them.
+type childType struct {
+	iface  string // the qualified interface, like expr.Expr
+	isList bool
+}
+
+// childTypes returns the interfaces that nodes implement: the node interface of each section, and the embedded
+// interface of a section if it is declared in another section, like expr.Statement.
+func childTypes(packages []packageSpec) map[string]bool {
+	sections := map[string]bool{}
+	for _, pkg := range packages {
+		sections[pkg.name] = true
+	}
+
+	ifaces := map[string]bool{}
+	for _, pkg := range packages {
+		ifaces[pkg.name+"."+pkg.nodeType] = true
+		if match := qualifier.FindStringSubmatch(pkg.embed); match != nil && sections[match[1]] {
+			ifaces[pkg.embed] = true
+		}
+	}
+
+	return ifaces
+}
+
+// child returns the traversed type of a field in pkg, and whether it is one.
+func child(pkg packageSpec, field fieldSpec, ifaces map[string]bool) (childType, bool) {
+	typeName := strings.TrimPrefix(field.typeName, "[]")
+	if !strings.Contains(typeName, ".") {
+		typeName = pkg.name + "." + typeName
+	}
+
+	return childType{iface: typeName, isList: strings.HasPrefix(field.typeName, "[]")}, ifaces[typeName]
+}
+
+// helperName names the rewrite helper for an interface, such as rewriteExpr for expr.Expr.
+func helperName(iface string) string {
+	return "rewrite" + iface[strings.Index(iface, ".")+1:]
+}
+
+// generateTraversal returns the formatted source code of the walkChildren and rewriteChildren functions, which the
+// ast package in pkgName uses to visit the children of every node in packages. A node's children are its fields of
+// a node interface type, or lists of them, in the order of the fields.
+func generateTraversal(pkgName string, packages []packageSpec) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by tools/generateast from ast.spec. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %v\n\n", pkgName)
+
+	var paths []string
+	for _, pkg := range packages {
+		paths = append(paths, fmt.Sprintf("%q", modulePath+"/"+pkg.name))
+	}
+	sort.Strings(paths)
+	fmt.Fprintf(&buf, "import (\n%v\n)\n\n", strings.Join(paths, "\n"))
+
+	ifaces := childTypes(packages)
+
+	fmt.Fprintf(&buf, "// walkChildren walks each of node's children.\n")
+	fmt.Fprintf(&buf, "func walkChildren(node Node, fn func(Node) bool) {\nswitch n := node.(type) {\n")
+	for _, pkg := range packages {
+		for _, node := range pkg.nodes {
+			var lines []string
+			for _, field := range node.fields {
+				childType, ok := child(pkg, field, ifaces)
+				switch {
+				case !ok:
+				case childType.isList:
+					lines = append(lines, fmt.Sprintf("for _, child := range n.%v {\nWalk(child, fn)\n}", field.name))
+				default:
+					lines = append(lines, fmt.Sprintf("Walk(n.%v, fn)", field.name))
+				}
+			}
+			if len(lines) > 0 {
+				fmt.Fprintf(&buf, "case *%v.%v:\n%v\n", pkg.name, node.name, strings.Join(lines, "\n"))
+			}
+		}
+	}
+	fmt.Fprintf(&buf, "}\n}\n\n")
+
+	fmt.Fprintf(&buf, "// rewriteChildren replaces each of node's children with the result of rewriting it.\n")
+	fmt.Fprintf(&buf, "func rewriteChildren(node Node, fn func(Node) Node) {\nswitch n := node.(type) {\n")
+	for _, pkg := range packages {
+		for _, node := range pkg.nodes {
+			var lines []string
+			for _, field := range node.fields {
+				childType, ok := child(pkg, field, ifaces)
+				if !ok {
+					continue
+				}
+
+				helper := helperName(childType.iface)
+				if childType.isList {
+					helper += "s"
+				}
+				lines = append(lines, fmt.Sprintf("n.%v = %v(n.%v, fn)", field.name, helper, field.name))
+			}
+			if len(lines) > 0 {
+				fmt.Fprintf(&buf, "case *%v.%v:\n%v\n", pkg.name, node.name, strings.Join(lines, "\n"))
+			}
+		}
+	}
+	fmt.Fprintf(&buf, "}\n}\n")
+
+	var sorted []string
+	for iface := range ifaces {
+		sorted = append(sorted, iface)
+	}
+	sort.Strings(sorted)
+
+	for _, iface := range sorted {
+		helper := helperName(iface)
+		fmt.Fprintf(&buf, "\nfunc %v(node %v, fn func(Node) Node) %v {\n", helper, iface, iface)
+		fmt.Fprintf(&buf, "rewritten := Rewrite(node, fn)\nif rewritten == nil {\nreturn nil\n}\n\n")
+		fmt.Fprintf(&buf, "return rewritten.(%v)\n}\n", iface)
+
+		fmt.Fprintf(&buf, "\nfunc %vs(nodes []%v, fn func(Node) Node) []%v {\n", helper, iface, iface)
+		fmt.Fprintf(&buf, "if nodes == nil {\nreturn nil\n}\n\n")
+		fmt.Fprintf(&buf, "rewritten := nodes[:0]\nfor _, node := range nodes {\n")
+		fmt.Fprintf(&buf, "if node := %v(node, fn); node != nil {\nrewritten = append(rewritten, node)\n}\n}\n\n", helper)
+		fmt.Fprintf(&buf, "return rewritten\n}\n")
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// writeRangeMethod writes the Pos or End method of node, which returns the position of the first of fields that is
+// used.
+func writeRangeMethod(buf *bytes.Buffer, node nodeSpec, method string, fields []rangeField) {
+	receiver := receiverName(node.name)
+	last := fields[len(fields)-1]
+	if len(fields) == 1 {
+		fmt.Fprintf(buf, "func (%v *%v) %v() toks.Position { return %v }\n\n", receiver, node.name, method,
+			positionOf(receiver+"."+last.field.name, last.field.typeName, method))
+		return
+	}
+
+	fmt.Fprintf(buf, "func (%v *%v) %v() toks.Position {\n", receiver, node.name, method)
+	for _, field := range fields[:len(fields)-1] {
+		value := receiver + "." + field.field.name
+		condition := receiver + "." + field.guard
+		if field.guard == "" {
+			condition = isPresent(value, field.field.typeName)
+		}
+		fmt.Fprintf(buf, "if %v {\nreturn %v\n}\n", condition, positionOf(value, field.field.typeName, method))
+	}
+	fmt.Fprintf(buf, "return %v\n}\n\n", positionOf(receiver+"."+last.field.name, last.field.typeName, method))
+}
+
+// positionOf returns the code for the Pos or End of value, whose type is typeName. A list's range starts at its
+// first element and ends at its last one.
+func positionOf(value string, typeName string, method string) string {
+	switch {
+	case typeName == "toks.Position":
+		return value
+	case typeName == "toks.Token" && method == "Pos":
+		return value + ".Pos"
+	case strings.HasPrefix(typeName, "[]") && method == "Pos":
+		return positionOf(value+"[0]", typeName[2:], method)
+	case strings.HasPrefix(typeName, "[]"):
+		return positionOf(fmt.Sprintf("%v[len(%v)-1]", value, value), typeName[2:], method)
+	default:
+		return value + "." + method + "()"
+	}
+}
+
+// isPresent returns the code that reports whether value, whose type is typeName, is in the source code.
+func isPresent(value string, typeName string) string {
+	switch {
+	case typeName == "toks.Position":
+		return value + ".IsValid()"
+	case typeName == "toks.Token":
+		return value + ".Pos.IsValid()"
+	case strings.HasPrefix(typeName, "[]"):
+		return fmt.Sprintf("len(%v) > 0", value)
+	default:
+		return value + " != nil"
+	}
+}
+
+// receiverName names a node's receiver after its type, such as setIndex for SetIndex. A name that would be a
+// keyword or shadow a predeclared identifier is shortened to its first letter, so Var's receiver is v.
+func receiverName(nodeName string) string {
+	runes := []rune(nodeName)
+	runes[0] = unicode.ToLower(runes[0])
+	name := string(runes)
+
+	if token.IsKeyword(name) || types.Universe.Lookup(name) != nil {
+		return string(runes[0])
+	}
+
+	return name
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedFilesAreUpToDate(t *testing.T) {
+	file, err := os.Open("ast.spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	packages, err := parseSpec(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pkg := range packages {
+		expected, err := generate(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join("..", "..", pkg.name, pkg.name+".go")
+		actual, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(actual) != string(expected) {
+			t.Errorf("%v is out of date with ast.spec. Run go generate ./%v.", path, pkg.name)
+		}
+	}
+
+	expected, err := generateTraversal("ast", packages)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join("..", "..", "ast", "children.go")
+	actual, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(actual) != string(expected) {
+		t.Errorf("%v is out of date with ast.spec. Run go generate ./ast.", path)
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected string
+	}{
+		{"Binary: Left Expr", "1: node before the first package line"},
+		{"package expr", "1: expect 'package <name> <node interface>'"},
+		{"package expr Expr colour=red", `1: unknown package option "colour=red"`},
+		{"package expr Expr\nBinary Left Expr", "2: expect ':' after node name"},
+		{"package expr Expr\nbinary [Left..Left]: Left Expr", `2: node name "binary" must be exported`},
+		{"package expr Expr\nBinary [Left..Right]: Left, Right Expr", `2: expect '<Field> <Type>' in Binary, got "Left"`},
+		{"package expr Expr\nBinary: Left Expr", "2: expect '[<pos>..<end>]' after Binary"},
+		{"package expr Expr\nBinary [Left..Right]: Left Expr", `2: Binary has no field "Right"`},
+		{"package expr Expr\nUpdate [Target..Post?Target]: Target Expr", `2: Update has no bool field "Post"`},
+		{"package expr Expr\nUpdate [Target..Prefix?Target]: Target Expr, Prefix bool",
+			"2: the last field in the range of Update must not have a guard"},
+	}
+
+	for _, test := range tests {
+		_, err := parseSpec(strings.NewReader(test.spec))
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Expected error %q for spec %q, but got %v", test.expected, test.spec, err)
+		}
+	}
+}
+
+func TestGenerateRange(t *testing.T) {
+	spec := "package stmt Stmt\nReturn [Prefix?Keyword|Values|Value..Value|Keyword]: Keyword toks.Token, Values []Stmt, Value Stmt, Prefix bool"
+	packages, err := parseSpec(strings.NewReader(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generate(packages[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"func (r *Return) Pos() toks.Position {\n\tif r.Prefix {\n\t\treturn r.Keyword.Pos\n\t}\n" +
+			"\tif len(r.Values) > 0 {\n\t\treturn r.Values[0].Pos()\n\t}\n\treturn r.Value.Pos()\n}",
+		"func (r *Return) End() toks.Position {\n\tif r.Value != nil {\n\t\treturn r.Value.End()\n\t}\n" +
+			"\treturn r.Keyword.End()\n}",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("Expected the generated code to contain\n%v\nbut it was\n%s", expected, src)
+		}
+	}
+}
+
+func TestReceiverName(t *testing.T) {
+	expected := map[string]string{"Binary": "binary", "SetIndex": "setIndex", "Var": "v", "Map": "m", "Print": "p"}
+	for nodeName, name := range expected {
+		if actual := receiverName(nodeName); actual != name {
+			t.Errorf("Expected the receiver of %v to be %v, but it was %v", nodeName, name, actual)
+		}
+	}
+}
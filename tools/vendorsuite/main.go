@@ -0,0 +1,110 @@
+// Command vendorsuite copies the test/ directory of the Crafting Interpreters repository at a given commit into
+// loxtest/testdata/craftinginterpreters, replacing the scripts that are there. Run it from the root of the module:
+//
+//	go run ./tools/vendorsuite -commit <hash>
+//
+// It writes a README.md next to the scripts that records the commit they came from.
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const archiveURL = "https://codeload.github.com/munificent/craftinginterpreters/tar.gz/"
+
+const readme = `These scripts are the ` + "`test/`" + ` directory of the Crafting Interpreters repository
+(https://github.com/munificent/craftinginterpreters) at commit %v, which is MIT licensed. They
+were copied by ` + "`go run ./tools/vendorsuite -commit %v`" + `, so update them with that rather than by hand.
+` + "`TestConformance`" + ` in ` + "`conformance_test.go`" + ` runs them, skipping the parts listed in ` + "`skipped`" + ` there.
+`
+
+func main() {
+	commit := flag.String("commit", "", "upstream commit to copy the suite from")
+	dir := flag.String("dir", filepath.Join("loxtest", "testdata", "craftinginterpreters"), "directory to copy it to")
+	flag.Parse()
+
+	if *commit == "" {
+		log.Fatal("vendorsuite: -commit is required")
+	}
+
+	response, err := http.Get(archiveURL + *commit)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		log.Fatalf("vendorsuite: downloading %v: %v", *commit, response.Status)
+	}
+
+	if err := os.RemoveAll(*dir); err != nil {
+		log.Fatal(err)
+	}
+
+	count, err := extractSuite(response.Body, *dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(*dir, "README.md"), []byte(fmt.Sprintf(readme, *commit, *commit)), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Copied %d files from %v to %v.\n", count, *commit, *dir)
+}
+
+// extractSuite writes the files under test/ in the gzipped tarball of the repository read from r to dir, and
+// returns how many it wrote. GitHub puts everything in the tarball under one top-level directory.
+func extractSuite(r io.Reader, dir string) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	count := 0
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		name := path.Clean(header.Name)
+		parts := strings.SplitN(name, "/", 3)
+		if header.Typeflag != tar.TypeReg || len(parts) != 3 || parts[1] != "test" {
+			continue
+		}
+		if strings.HasPrefix(parts[2], "../") {
+			return count, fmt.Errorf("vendorsuite: %v is outside the archive", header.Name)
+		}
+
+		dest := filepath.Join(dir, filepath.FromSlash(parts[2]))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return count, err
+		}
+
+		contents, err := ioutil.ReadAll(archive)
+		if err != nil {
+			return count, err
+		}
+		if err := ioutil.WriteFile(dest, contents, 0644); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractSuite(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+
+	files := map[string]string{
+		"craftinginterpreters-abc/test/empty_file.lox":    "",
+		"craftinginterpreters-abc/test/bool/equality.lox": "print true == true; // expect: true\n",
+		"craftinginterpreters-abc/test.dart":              "not part of the suite",
+		"craftinginterpreters-abc/java/com/Lox.java":      "not part of the suite",
+	}
+	for name, contents := range files {
+		header := &tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(contents))}
+		if err := archive.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "vendorsuite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	count, err := extractSuite(&buf, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 files to be copied, but %d were", count)
+	}
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "bool", "equality.lox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != files["craftinginterpreters-abc/test/bool/equality.lox"] {
+		t.Errorf("Expected bool/equality.lox to be copied as it is, but it was %q", contents)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test.dart")); !os.IsNotExist(err) {
+		t.Errorf("Expected test.dart not to be copied, but got %v", err)
+	}
+}